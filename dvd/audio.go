@@ -0,0 +1,153 @@
+package dvd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BitsPerSample returns the sample bit depth from the stream's quantization
+// ("16bit", "20bit" or "24bit"), or 0 when it is unknown or not applicable,
+// as with the "drc" value lsdvd reports for compressed formats such as ac3
+func (a *AudioStream) BitsPerSample() int {
+	switch strings.ToLower(strings.TrimSpace(a.Quantization)) {
+	case "16bit":
+		return 16
+	case "20bit":
+		return 20
+	case "24bit":
+		return 24
+	default:
+		return 0
+	}
+}
+
+// ChannelLayout returns a layout name for the stream's channel count, such as
+// "Stereo" or "5.1", falling back to "N channels" for uncommon counts
+func (a *AudioStream) ChannelLayout() string {
+	switch a.Channels {
+	case 1:
+		return "Mono"
+	case 2:
+		return "Stereo"
+	case 6:
+		return "5.1"
+	case 8:
+		return "7.1"
+	default:
+		return fmt.Sprintf("%d channels", a.Channels)
+	}
+}
+
+// IsCommentary reports whether the stream's content marks it as a commentary
+// track, such as lsdvd's "Comments1" or "Director's Comments"
+func (a *AudioStream) IsCommentary() bool {
+	content := strings.ToLower(a.Content)
+	return strings.Contains(content, "comment") || strings.Contains(content, "director")
+}
+
+// StreamIDInt returns the stream's ID as an integer. The ID may be hex with a
+// "0x" prefix, as lsdvd writes it (e.g. "0x80"), or decimal.
+func (a *AudioStream) StreamIDInt() (int, error) {
+	return parseStreamID(a.StreamID)
+}
+
+// parseStreamID parses a "0x"-prefixed hex or decimal stream ID
+func parseStreamID(id string) (int, error) {
+	s := strings.TrimSpace(id)
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+		base = 16
+	}
+	value, err := strconv.ParseInt(s, base, 0)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid stream ID %q", id)
+	}
+	return int(value), nil
+}
+
+// IsLossless reports whether the stream is uncompressed PCM audio ("lpcm" or
+// "pcm"), as opposed to a lossy codec such as ac3, dts or mp2
+func (a *AudioStream) IsLossless() bool {
+	switch strings.ToLower(strings.TrimSpace(a.Format)) {
+	case "lpcm", "pcm":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSurroundSound reports whether the stream has more than two channels
+func (a *AudioStream) IsSurroundSound() bool {
+	return a.Channels > 2
+}
+
+// IsMono reports whether the stream has a single channel
+func (a *AudioStream) IsMono() bool {
+	return a.Channels == 1
+}
+
+// IsStereo reports whether the stream has two channels
+func (a *AudioStream) IsStereo() bool {
+	return a.Channels == 2
+}
+
+// GetChannelDescription returns a display name for the stream's channel
+// layout, such as "Stereo" or "Surround 5.1". A count that is zero or
+// negative, meaning it wasn't parsed, is "Unknown", and uncommon counts are
+// described as "Channels: N".
+func (a *AudioStream) GetChannelDescription() string {
+	switch {
+	case a.Channels <= 0:
+		return "Unknown"
+	case a.Channels == 1:
+		return "Mono"
+	case a.Channels == 2:
+		return "Stereo"
+	case a.Channels == 4:
+		return "Surround 4.0"
+	case a.Channels == 6:
+		return "Surround 5.1"
+	case a.Channels == 8:
+		return "Surround 7.1"
+	default:
+		return fmt.Sprintf("Channels: %d", a.Channels)
+	}
+}
+
+// GetAPModeDescription returns a display name for the stream's application
+// mode: "Normal", "Karaoke" or "Surround", or "Unknown (N)" for other values
+func (a *AudioStream) GetAPModeDescription() string {
+	switch a.APMode {
+	case apModeNormal:
+		return "Normal"
+	case apModeKaraoke:
+		return "Karaoke"
+	case apModeSurround:
+		return "Surround"
+	default:
+		return fmt.Sprintf("Unknown (%d)", a.APMode)
+	}
+}
+
+// GetFormatDescription returns the display name of the stream's codec, such
+// as "Dolby Digital" for ac3, or the format uppercased for codecs without a
+// known name. An empty format is "Unknown".
+func (a *AudioStream) GetFormatDescription() string {
+	format := strings.TrimSpace(a.Format)
+	switch strings.ToLower(format) {
+	case "":
+		return "Unknown"
+	case "ac3":
+		return "Dolby Digital"
+	case "dts":
+		return "DTS"
+	case "mp2":
+		return "MPEG Audio"
+	case "lpcm":
+		return "PCM (Lossless)"
+	default:
+		return strings.ToUpper(format)
+	}
+}
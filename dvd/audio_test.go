@@ -0,0 +1,232 @@
+package dvd
+
+import (
+	"testing"
+)
+
+// TestBitsPerSample tests interpreting the quantization field
+func TestBitsPerSample(t *testing.T) {
+	testCases := []struct {
+		quantization string
+		expected     int
+	}{
+		{"16bit", 16},
+		{"20bit", 20},
+		{"24bit", 24},
+		{"24BIT", 24},
+		{"drc", 0},
+		{"", 0},
+		{"32bit", 0},
+	}
+
+	for _, tc := range testCases {
+		audio := AudioStream{Quantization: tc.quantization}
+		if got := audio.BitsPerSample(); got != tc.expected {
+			t.Errorf("BitsPerSample() for %q = %d, expected %d", tc.quantization, got, tc.expected)
+		}
+	}
+}
+
+// TestChannelLayout tests channel count to layout name mapping
+func TestChannelLayout(t *testing.T) {
+	testCases := []struct {
+		channels int
+		expected string
+	}{
+		{1, "Mono"},
+		{2, "Stereo"},
+		{6, "5.1"},
+		{8, "7.1"},
+		{3, "3 channels"},
+		{0, "0 channels"},
+	}
+
+	for _, tc := range testCases {
+		audio := AudioStream{Channels: tc.channels}
+		if got := audio.ChannelLayout(); got != tc.expected {
+			t.Errorf("ChannelLayout() for %d channels = %q, expected %q", tc.channels, got, tc.expected)
+		}
+	}
+}
+
+// TestIsCommentary tests recognising commentary tracks from their content
+func TestIsCommentary(t *testing.T) {
+	testCases := []struct {
+		content  string
+		expected bool
+	}{
+		{"Director's Comments", true},
+		{"director's comments", true},
+		{"Comments1", true},
+		{"Normal", false},
+		{"Undefined", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		audio := AudioStream{Content: tc.content}
+		if got := audio.IsCommentary(); got != tc.expected {
+			t.Errorf("IsCommentary() for %q = %v, expected %v", tc.content, got, tc.expected)
+		}
+	}
+}
+
+// TestStreamIDInt tests parsing stream IDs into integers
+func TestStreamIDInt(t *testing.T) {
+	testCases := []struct {
+		streamID string
+		expected int
+		wantErr  bool
+	}{
+		{"0x80", 128, false},
+		{"0X8a", 138, false},
+		{"0x20", 32, false},
+		{"128", 128, false},
+		{"0xzz", 0, true},
+		{"0x", 0, true},
+		{"-1", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tc := range testCases {
+		audio := AudioStream{StreamID: tc.streamID}
+		got, err := audio.StreamIDInt()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("StreamIDInt() for %q error = %v, wantErr %v", tc.streamID, err, tc.wantErr)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("StreamIDInt() for %q = %d, expected %d", tc.streamID, got, tc.expected)
+		}
+	}
+
+	sub := SubtitleStream{StreamID: "0x20"}
+	if got, err := sub.StreamIDInt(); err != nil || got != 32 {
+		t.Errorf("SubtitleStream.StreamIDInt() = %d, %v, expected 32", got, err)
+	}
+	sub.StreamID = "sub1"
+	if _, err := sub.StreamIDInt(); err == nil {
+		t.Error("Expected error for malformed subtitle stream ID")
+	}
+}
+
+// TestIsLossless tests classifying audio formats as lossless
+func TestIsLossless(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected bool
+	}{
+		{"LPCM", true},
+		{"lpcm", true},
+		{"pcm", true},
+		{"ac3", false},
+		{"dts", false},
+		{"mp2", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		audio := AudioStream{Format: tc.format}
+		if got := audio.IsLossless(); got != tc.expected {
+			t.Errorf("IsLossless() for %q = %v, expected %v", tc.format, got, tc.expected)
+		}
+	}
+}
+
+// TestChannelHelpers tests the mono, stereo and surround helpers
+func TestChannelHelpers(t *testing.T) {
+	testCases := []struct {
+		channels int
+		mono     bool
+		stereo   bool
+		surround bool
+	}{
+		{0, false, false, false},
+		{1, true, false, false},
+		{2, false, true, false},
+		{4, false, false, true},
+		{6, false, false, true},
+		{8, false, false, true},
+	}
+
+	for _, tc := range testCases {
+		audio := AudioStream{Channels: tc.channels}
+		if got := audio.IsMono(); got != tc.mono {
+			t.Errorf("IsMono() for %d channels = %v, expected %v", tc.channels, got, tc.mono)
+		}
+		if got := audio.IsStereo(); got != tc.stereo {
+			t.Errorf("IsStereo() for %d channels = %v, expected %v", tc.channels, got, tc.stereo)
+		}
+		if got := audio.IsSurroundSound(); got != tc.surround {
+			t.Errorf("IsSurroundSound() for %d channels = %v, expected %v", tc.channels, got, tc.surround)
+		}
+	}
+}
+
+// TestGetChannelDescription tests channel count to description mapping
+func TestGetChannelDescription(t *testing.T) {
+	testCases := []struct {
+		channels int
+		expected string
+	}{
+		{0, "Unknown"},
+		{1, "Mono"},
+		{2, "Stereo"},
+		{4, "Surround 4.0"},
+		{6, "Surround 5.1"},
+		{8, "Surround 7.1"},
+		{3, "Channels: 3"},
+		{-1, "Unknown"},
+	}
+
+	for _, tc := range testCases {
+		audio := AudioStream{Channels: tc.channels}
+		if got := audio.GetChannelDescription(); got != tc.expected {
+			t.Errorf("GetChannelDescription() for %d channels = %q, expected %q", tc.channels, got, tc.expected)
+		}
+	}
+}
+
+// TestGetAPModeDescription tests ap_mode to description mapping
+func TestGetAPModeDescription(t *testing.T) {
+	testCases := []struct {
+		apMode   int
+		expected string
+	}{
+		{0, "Normal"},
+		{1, "Karaoke"},
+		{2, "Surround"},
+		{3, "Unknown (3)"},
+		{-1, "Unknown (-1)"},
+	}
+
+	for _, tc := range testCases {
+		audio := AudioStream{APMode: tc.apMode}
+		if got := audio.GetAPModeDescription(); got != tc.expected {
+			t.Errorf("GetAPModeDescription() for %d = %q, expected %q", tc.apMode, got, tc.expected)
+		}
+	}
+}
+
+// TestGetFormatDescription tests codec display names
+func TestGetFormatDescription(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{"ac3", "Dolby Digital"},
+		{"dts", "DTS"},
+		{"mp2", "MPEG Audio"},
+		{"lpcm", "PCM (Lossless)"},
+		{"AC3", "Dolby Digital"},
+		{"sdds", "SDDS"},
+		{"", "Unknown"},
+	}
+
+	for _, tc := range testCases {
+		audio := AudioStream{Format: tc.format}
+		if got := audio.GetFormatDescription(); got != tc.expected {
+			t.Errorf("GetFormatDescription() for %q = %q, expected %q", tc.format, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package dvd
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// chapterSpan is the start and end time of a chapter in seconds, measured from
+// the beginning of the track
+type chapterSpan struct {
+	Chapter    *Chapter
+	Start, End float64
+}
+
+// chapterSpans returns the start and end time of each chapter in the track,
+// computed from the cumulative chapter lengths
+func (t *Track) chapterSpans() []chapterSpan {
+	spans := make([]chapterSpan, len(t.Chapters))
+	var offset float64
+	for i := range t.Chapters {
+		chapter := &t.Chapters[i]
+		spans[i] = chapterSpan{Chapter: chapter, Start: offset, End: offset + chapter.Length}
+		offset += chapter.Length
+	}
+	return spans
+}
+
+// FFMetadataChapters returns the track's chapters in FFmpeg's ffmetadata format,
+// suitable for remuxing chapter markers with "ffmpeg -i metadata.txt -map_chapters".
+// Times are written in milliseconds.
+func (t *Track) FFMetadataChapters() ([]byte, error) {
+	if len(t.Chapters) == 0 {
+		return nil, fmt.Errorf("track %d has no chapters", t.Index)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(";FFMETADATA1\n")
+	for _, span := range t.chapterSpans() {
+		buf.WriteString("\n[CHAPTER]\n")
+		buf.WriteString("TIMEBASE=1/1000\n")
+		fmt.Fprintf(&buf, "START=%d\n", secondsToMillis(span.Start))
+		fmt.Fprintf(&buf, "END=%d\n", secondsToMillis(span.End))
+		fmt.Fprintf(&buf, "title=Chapter %d\n", span.Chapter.Index)
+	}
+	return buf.Bytes(), nil
+}
+
+// secondsToMillis converts seconds to whole milliseconds
+func secondsToMillis(seconds float64) int64 {
+	return int64(math.Round(seconds * 1000))
+}
+
+// ToWebVTTChapters returns the track's chapters as a WebVTT file with one cue
+// per chapter. Zero-length chapters are skipped.
+func (t *Track) ToWebVTTChapters() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("WEBVTT\n")
+
+	cues := 0
+	for _, span := range t.chapterSpans() {
+		if span.Chapter.Length <= 0 {
+			continue
+		}
+		cues++
+		fmt.Fprintf(&buf, "\n%d\n%s --> %s\nChapter %d\n", cues,
+			formatVTTTimestamp(span.Start), formatVTTTimestamp(span.End), span.Chapter.Index)
+	}
+
+	if cues == 0 {
+		return nil, fmt.Errorf("track %d has no chapters", t.Index)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatVTTTimestamp formats seconds as a WebVTT HH:MM:SS.mmm timestamp
+func formatVTTTimestamp(seconds float64) string {
+	millis := secondsToMillis(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d",
+		millis/3600000, millis/60000%60, millis/1000%60, millis%1000)
+}
+
+// FrameCount returns the number of video frames in the chapter at the given
+// frame rate, usually the FPS of the containing track
+func (c *Chapter) FrameCount(fps float64) int64 {
+	return frameCount(c.Length, fps)
+}
+
+// GetTotalChapterLength returns the combined length of the track's chapters in
+// seconds. It can fall short of the track length when chapters don't cover
+// the whole track, so GetTotalChapterLength()/Length gives the coverage.
+func (t *Track) GetTotalChapterLength() float64 {
+	var total float64
+	for _, chapter := range t.Chapters {
+		total += chapter.Length
+	}
+	return total
+}
+
+// GetLongestChapter returns the longest chapter of the track, or nil if it has
+// no chapters. Of chapters with equal lengths, the lowest index wins.
+func (t *Track) GetLongestChapter() *Chapter {
+	return t.findChapter(func(a, b *Chapter) bool { return a.Length > b.Length })
+}
+
+// GetShortestChapter returns the shortest chapter of the track, or nil if it
+// has no chapters. Of chapters with equal lengths, the lowest index wins.
+func (t *Track) GetShortestChapter() *Chapter {
+	return t.findChapter(func(a, b *Chapter) bool { return a.Length < b.Length })
+}
+
+// findChapter returns the chapter that is better than every other according
+// to better, breaking ties by lowest index
+func (t *Track) findChapter(better func(a, b *Chapter) bool) *Chapter {
+	var best *Chapter
+	for i := range t.Chapters {
+		chapter := &t.Chapters[i]
+		if best == nil || better(chapter, best) ||
+			(chapter.Length == best.Length && chapter.Index < best.Index) {
+			best = chapter
+		}
+	}
+	return best
+}
+
+// GetChapterByIndex returns the chapter with the given 1-based lsdvd index, or
+// nil if there is none
+func (t *Track) GetChapterByIndex(ix int) *Chapter {
+	for i := range t.Chapters {
+		if t.Chapters[i].Index == ix {
+			return &t.Chapters[i]
+		}
+	}
+	return nil
+}
+
+// GetChapterDurations returns the length in seconds of each chapter, in chapter order
+func (t *Track) GetChapterDurations() []float64 {
+	durations := make([]float64, len(t.Chapters))
+	for i, chapter := range t.Chapters {
+		durations[i] = chapter.Length
+	}
+	return durations
+}
+
+// GetChapterOffsets returns the start time in seconds of each chapter in the
+// track, measured from the beginning of the track
+func (t *Track) GetChapterOffsets() []float64 {
+	offsets := make([]float64, len(t.Chapters))
+	for i, span := range t.chapterSpans() {
+		offsets[i] = span.Start
+	}
+	return offsets
+}
+
+// GetChaptersInTimeRange returns the chapters whose interval [start, start +
+// length) overlaps [startSeconds, endSeconds), in chapter order. Chapters that
+// only partly overlap the range are included. An empty or inverted range
+// returns no chapters.
+func (t *Track) GetChaptersInTimeRange(startSeconds, endSeconds float64) []*Chapter {
+	chapters := []*Chapter{}
+	if startSeconds >= endSeconds {
+		return chapters
+	}
+	for i, offset := range t.GetChapterOffsets() {
+		chapter := &t.Chapters[i]
+		if offset < endSeconds && offset+chapter.Length > startSeconds {
+			chapters = append(chapters, chapter)
+		}
+	}
+	return chapters
+}
@@ -0,0 +1,239 @@
+package dvd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newChapterTestTrack returns a track with three chapters for chapter export tests
+func newChapterTestTrack() *Track {
+	return &Track{
+		Index:  1,
+		Length: 1771.36,
+		Chapters: []Chapter{
+			{Index: 1, Length: 735.2, StartCell: 1},
+			{Index: 2, Length: 423.2, StartCell: 2},
+			{Index: 3, Length: 612.96, StartCell: 3},
+		},
+	}
+}
+
+// TestFFMetadataChapters tests the ffmetadata chapter export
+func TestFFMetadataChapters(t *testing.T) {
+	track := newChapterTestTrack()
+
+	data, err := track.FFMetadataChapters()
+	if err != nil {
+		t.Fatalf("FFMetadataChapters failed: %v", err)
+	}
+	output := string(data)
+
+	if !strings.HasPrefix(output, ";FFMETADATA1\n") {
+		t.Errorf("Expected ;FFMETADATA1 header, got:\n%s", output)
+	}
+	if count := strings.Count(output, "[CHAPTER]"); count != 3 {
+		t.Errorf("Expected 3 chapter blocks, got %d", count)
+	}
+	if count := strings.Count(output, "TIMEBASE=1/1000"); count != 3 {
+		t.Errorf("Expected 3 TIMEBASE lines, got %d", count)
+	}
+
+	// The second chapter starts where the first ends
+	expected := "[CHAPTER]\nTIMEBASE=1/1000\nSTART=735200\nEND=1158400\ntitle=Chapter 2\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected second chapter block %q, got:\n%s", expected, output)
+	}
+	if !strings.Contains(output, "END=1771360\n") {
+		t.Errorf("Expected last chapter to end at 1771360, got:\n%s", output)
+	}
+
+	empty := &Track{Index: 2}
+	if _, err := empty.FFMetadataChapters(); err == nil {
+		t.Error("Expected error for track without chapters, got nil")
+	}
+}
+
+// TestToWebVTTChapters tests the WebVTT chapter export
+func TestToWebVTTChapters(t *testing.T) {
+	track := newChapterTestTrack()
+	track.Chapters = append(track.Chapters, Chapter{Index: 4, Length: 0, StartCell: 4})
+	track.Chapters = append(track.Chapters, Chapter{Index: 5, Length: 3000.5, StartCell: 5})
+
+	data, err := track.ToWebVTTChapters()
+	if err != nil {
+		t.Fatalf("ToWebVTTChapters failed: %v", err)
+	}
+	output := string(data)
+
+	if !strings.HasPrefix(output, "WEBVTT\n") {
+		t.Errorf("Expected WEBVTT header, got:\n%s", output)
+	}
+	if strings.Contains(output, "Chapter 4") {
+		t.Errorf("Expected zero-length chapter 4 to be skipped, got:\n%s", output)
+	}
+	if !strings.Contains(output, "00:29:31.360 --> 01:19:31.860\nChapter 5\n") {
+		t.Errorf("Expected cue for chapter 5, got:\n%s", output)
+	}
+
+	var starts []time.Duration
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, " --> ")
+		if len(parts) != 2 {
+			continue
+		}
+		var h, m, s, ms int
+		if _, err := fmt.Sscanf(parts[0], "%d:%d:%d.%d", &h, &m, &s, &ms); err != nil {
+			t.Fatalf("Failed to parse timestamp %q: %v", parts[0], err)
+		}
+		starts = append(starts, time.Duration(h)*time.Hour+time.Duration(m)*time.Minute+
+			time.Duration(s)*time.Second+time.Duration(ms)*time.Millisecond)
+	}
+
+	if len(starts) != 4 {
+		t.Fatalf("Expected 4 cues, got %d", len(starts))
+	}
+	for i := 1; i < len(starts); i++ {
+		if starts[i] <= starts[i-1] {
+			t.Errorf("Cue %d starts at %v, not after cue %d at %v", i+1, starts[i], i, starts[i-1])
+		}
+	}
+
+	empty := &Track{Index: 2, Chapters: []Chapter{{Index: 1, Length: 0}}}
+	if _, err := empty.ToWebVTTChapters(); err == nil {
+		t.Error("Expected error for track without non-empty chapters, got nil")
+	}
+}
+
+// TestGetTotalChapterLength tests summing chapter lengths
+func TestGetTotalChapterLength(t *testing.T) {
+	if got := (&Track{}).GetTotalChapterLength(); got != 0 {
+		t.Errorf("Expected 0 for a track without chapters, got %v", got)
+	}
+
+	track := parseFixtureTrack(t, 1)
+	if len(track.Chapters) != 5 {
+		t.Fatalf("Expected 5 chapters, got %d", len(track.Chapters))
+	}
+	// 735.2 + 423.2 + 612.92 + 728.24 + 1.0, which covers the whole track
+	expected := 2500.56
+	if got := track.GetTotalChapterLength(); math.Abs(got-expected) > 0.001 {
+		t.Errorf("Expected total chapter length %.2f, got %.2f", expected, got)
+	}
+	if coverage := track.GetTotalChapterLength() / track.Length; math.Abs(coverage-1) > 0.001 {
+		t.Errorf("Expected full chapter coverage, got %.4f", coverage)
+	}
+}
+
+// TestGetLongestShortestChapter tests finding the longest and shortest chapters
+func TestGetLongestShortestChapter(t *testing.T) {
+	track := &Track{Chapters: []Chapter{
+		{Index: 1, Length: 735.2},
+		{Index: 2, Length: 423.2},
+		{Index: 3, Length: 612.92},
+		{Index: 4, Length: 735.2},
+		{Index: 5, Length: 1.0},
+	}}
+
+	if chapter := track.GetLongestChapter(); chapter == nil || chapter.Index != 1 {
+		t.Errorf("Expected longest chapter 1, got %+v", chapter)
+	}
+	if chapter := track.GetShortestChapter(); chapter == nil || chapter.Index != 5 {
+		t.Errorf("Expected shortest chapter 5, got %+v", chapter)
+	}
+
+	single := &Track{Chapters: []Chapter{{Index: 1, Length: 60}}}
+	longest, shortest := single.GetLongestChapter(), single.GetShortestChapter()
+	if longest == nil || longest != shortest {
+		t.Errorf("Expected the same chapter for a single-chapter track, got %+v and %+v", longest, shortest)
+	}
+
+	empty := &Track{}
+	if empty.GetLongestChapter() != nil || empty.GetShortestChapter() != nil {
+		t.Error("Expected nil chapters for a track without chapters")
+	}
+}
+
+// TestGetChapterByIndex tests chapter lookup by lsdvd index
+func TestGetChapterByIndex(t *testing.T) {
+	track := newChapterTestTrack()
+
+	if chapter := track.GetChapterByIndex(1); chapter != &track.Chapters[0] {
+		t.Errorf("Expected the first chapter for index 1, got %+v", chapter)
+	}
+	if chapter := track.GetChapterByIndex(3); chapter != &track.Chapters[2] {
+		t.Errorf("Expected the third chapter for index 3, got %+v", chapter)
+	}
+	for _, ix := range []int{0, 99} {
+		if chapter := track.GetChapterByIndex(ix); chapter != nil {
+			t.Errorf("Expected nil for index %d, got %+v", ix, chapter)
+		}
+	}
+}
+
+// TestGetChapterDurations tests listing chapter lengths
+func TestGetChapterDurations(t *testing.T) {
+	track := newChapterTestTrack()
+
+	durations := track.GetChapterDurations()
+	if len(durations) != len(track.Chapters) {
+		t.Fatalf("Expected %d durations, got %d", len(track.Chapters), len(durations))
+	}
+	for i, chapter := range track.Chapters {
+		if durations[i] != chapter.Length {
+			t.Errorf("Duration %d: expected %v, got %v", i, chapter.Length, durations[i])
+		}
+	}
+
+	if durations := (&Track{}).GetChapterDurations(); durations == nil || len(durations) != 0 {
+		t.Errorf("Expected an empty, non-nil slice for a track without chapters, got %v", durations)
+	}
+}
+
+// TestGetChapterOffsets tests chapter start times
+func TestGetChapterOffsets(t *testing.T) {
+	offsets := newChapterTestTrack().GetChapterOffsets()
+	expected := []float64{0, 735.2, 1158.4}
+	if len(offsets) != len(expected) {
+		t.Fatalf("Expected %d offsets, got %d", len(expected), len(offsets))
+	}
+	for i := range expected {
+		if math.Abs(offsets[i]-expected[i]) > 0.001 {
+			t.Errorf("Offset %d: expected %.2f, got %.2f", i, expected[i], offsets[i])
+		}
+	}
+}
+
+// TestGetChaptersInTimeRange tests finding chapters overlapping a time window
+func TestGetChaptersInTimeRange(t *testing.T) {
+	// Chapters span [0, 735.2), [735.2, 1158.4) and [1158.4, 1771.36)
+	track := newChapterTestTrack()
+
+	testCases := []struct {
+		name       string
+		start, end float64
+		expected   []int
+	}{
+		{"starts at a chapter boundary", 735.2, 800, []int{2}},
+		{"ends at a chapter boundary", 700, 735.2, []int{1}},
+		{"spans two full chapters", 0, 1158.4, []int{1, 2}},
+		{"partial overlaps", 700, 1200, []int{1, 2, 3}},
+		{"whole track", 0, 1771.36, []int{1, 2, 3}},
+		{"past the end", 1800, 1900, []int{}},
+		{"empty range", 800, 800, []int{}},
+		{"inverted range", 1200, 700, []int{}},
+	}
+
+	for _, tc := range testCases {
+		chapters := track.GetChaptersInTimeRange(tc.start, tc.end)
+		got := []int{}
+		for _, chapter := range chapters {
+			got = append(got, chapter.Index)
+		}
+		if chapters == nil || !equalInts(got, tc.expected) {
+			t.Errorf("%s: GetChaptersInTimeRange(%v, %v) = %v, expected %v", tc.name, tc.start, tc.end, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package dvd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// lengthDiffTolerance is the largest length difference in seconds that
+// DiffTracks treats as equal, to absorb rounding in the lsdvd output
+const lengthDiffTolerance = 0.001
+
+// DiffTracks compares the tracks of two DVDs and returns a human-readable
+// description of each difference in track count, length, resolution and
+// stream counts. Tracks are matched by index. Identical discs return no
+// differences.
+func (d *DVD) DiffTracks(other *DVD) []string {
+	diffs := []string{}
+	if len(d.Tracks) != len(other.Tracks) {
+		diffs = append(diffs, fmt.Sprintf("track count: %d vs %d", len(d.Tracks), len(other.Tracks)))
+	}
+
+	indexes := make(map[int]bool)
+	for _, track := range d.Tracks {
+		indexes[track.Index] = true
+	}
+	for _, track := range other.Tracks {
+		indexes[track.Index] = true
+	}
+	sorted := make([]int, 0, len(indexes))
+	for index := range indexes {
+		sorted = append(sorted, index)
+	}
+	sort.Ints(sorted)
+
+	for _, index := range sorted {
+		a := d.GetTrackByIndex(index)
+		b := other.GetTrackByIndex(index)
+		switch {
+		case b == nil:
+			diffs = append(diffs, fmt.Sprintf("track %d: missing from other disc", index))
+		case a == nil:
+			diffs = append(diffs, fmt.Sprintf("track %d: only on other disc", index))
+		default:
+			diffs = append(diffs, diffTrack(a, b)...)
+		}
+	}
+	return diffs
+}
+
+// diffTrack describes the differences between two tracks with the same index
+func diffTrack(a, b *Track) []string {
+	var diffs []string
+	if math.Abs(a.Length-b.Length) > lengthDiffTolerance {
+		diffs = append(diffs, fmt.Sprintf("track %d: length %.3f vs %.3f", a.Index, a.Length, b.Length))
+	}
+	if a.Width != b.Width || a.Height != b.Height {
+		diffs = append(diffs, fmt.Sprintf("track %d: resolution %dx%d vs %dx%d",
+			a.Index, a.Width, a.Height, b.Width, b.Height))
+	}
+	if len(a.AudioStreams) != len(b.AudioStreams) {
+		diffs = append(diffs, fmt.Sprintf("track %d: audio streams %d vs %d",
+			a.Index, len(a.AudioStreams), len(b.AudioStreams)))
+	}
+	if len(a.SubtitleStreams) != len(b.SubtitleStreams) {
+		diffs = append(diffs, fmt.Sprintf("track %d: subtitle streams %d vs %d",
+			a.Index, len(a.SubtitleStreams), len(b.SubtitleStreams)))
+	}
+	if len(a.Chapters) != len(b.Chapters) {
+		diffs = append(diffs, fmt.Sprintf("track %d: chapters %d vs %d",
+			a.Index, len(a.Chapters), len(b.Chapters)))
+	}
+	return diffs
+}
@@ -0,0 +1,36 @@
+package dvd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDiffTracks tests comparing the tracks of two discs
+func TestDiffTracks(t *testing.T) {
+	dvd, err := ParseFile("../source/s1d1.xml")
+	if err != nil {
+		t.Fatalf("Failed to parse DVD metadata: %v", err)
+	}
+
+	if diffs := dvd.DiffTracks(dvd); len(diffs) != 0 {
+		t.Errorf("Expected no differences comparing a disc to itself, got %v", diffs)
+	}
+
+	// Build a modified copy without touching the original tracks
+	other := *dvd
+	other.Tracks = append([]Track(nil), dvd.Tracks...)
+	other.Tracks[1].Height = 480
+	other.Tracks[1].AudioStreams = other.Tracks[1].AudioStreams[:1]
+	other.Tracks = other.Tracks[:len(other.Tracks)-1]
+
+	diffs := dvd.DiffTracks(&other)
+	expected := []string{
+		"track count: 10 vs 9",
+		"track 2: resolution 720x576 vs 720x480",
+		"track 2: audio streams 2 vs 1",
+		"track 10: missing from other disc",
+	}
+	if strings.Join(diffs, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected differences:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(diffs, "\n"))
+	}
+}
@@ -0,0 +1,118 @@
+package dvd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// ParseDirContext parses every .xml file in dir using up to concurrency
+// workers, or one per CPU if concurrency is not positive. The result is keyed
+// by file name. If ctx is cancelled, no further files are parsed and ctx.Err()
+// is returned. Otherwise the first parse error stops the remaining work and is
+// returned with the file name.
+func ParseDirContext(ctx context.Context, dir string, concurrency int) (map[string]*DVD, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list XML files in %s: %v", dir, err)
+	}
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		file string
+		dvd  *DVD
+		err  error
+	}
+	jobs := make(chan string)
+	results := make(chan result)
+
+	go func() {
+		defer close(jobs)
+		for _, file := range files {
+			select {
+			case jobs <- file:
+			case <-workCtx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				if workCtx.Err() != nil {
+					return
+				}
+				dvd, err := ParseFile(file)
+				select {
+				case results <- result{file, dvd, err}:
+				case <-workCtx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	dvds := make(map[string]*DVD, len(files))
+	var firstErr error
+	for r := range results {
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+				cancel()
+			}
+			continue
+		}
+		dvds[filepath.Base(r.file)] = r.dvd
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return dvds, nil
+}
+
+// FindContentInDir parses every .xml file in dir and returns the tracks and
+// chapters around targetMinutes, within toleranceMinutes, keyed by file name.
+// Only files with matches are included. Files that can't be parsed are
+// skipped, and their errors are joined into the returned error alongside
+// whatever matches were found.
+func FindContentInDir(dir string, targetMinutes, toleranceMinutes float64) (map[string][]ContentMatch, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list XML files in %s: %v", dir, err)
+	}
+
+	results := make(map[string][]ContentMatch)
+	var errs []error
+	for _, file := range files {
+		dvd, err := ParseFile(file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if matches := dvd.FindContentAroundDuration(targetMinutes, toleranceMinutes); len(matches) > 0 {
+			results[filepath.Base(file)] = matches
+		}
+	}
+	return results, errors.Join(errs...)
+}
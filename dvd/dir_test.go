@@ -0,0 +1,107 @@
+package dvd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestParseDirContext tests parsing every file in a directory concurrently
+func TestParseDirContext(t *testing.T) {
+	sourceDir := "../source"
+	files, err := filepath.Glob(filepath.Join(sourceDir, "*.xml"))
+	if err != nil || len(files) == 0 {
+		t.Skipf("No XML files found in %s, skipping test", sourceDir)
+	}
+
+	dvds, err := ParseDirContext(context.Background(), sourceDir, 4)
+	if err != nil {
+		t.Fatalf("ParseDirContext failed: %v", err)
+	}
+	if len(dvds) != len(files) {
+		t.Errorf("Expected %d DVDs, got %d", len(files), len(dvds))
+	}
+	if dvd := dvds["s1d1.xml"]; dvd == nil || len(dvd.Tracks) != 10 {
+		t.Errorf("Expected s1d1.xml with 10 tracks in results")
+	}
+}
+
+// TestParseDirContextCancelled tests that a cancelled context stops parsing
+func TestParseDirContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	dvds, err := ParseDirContext(ctx, "../source", 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if dvds != nil {
+		t.Errorf("Expected no results after cancellation, got %d", len(dvds))
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected prompt return after cancellation, took %v", elapsed)
+	}
+}
+
+// TestParseDirContextError tests that a bad file fails the whole directory
+func TestParseDirContextError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "good.xml"), []byte("<lsdvd><device>./good</device></lsdvd>"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "empty.xml"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	_, err := ParseDirContext(context.Background(), dir, 0)
+	if !errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("Expected ErrEmptyDocument, got %v", err)
+	}
+}
+
+// TestFindContentInDir tests searching a directory for episode-length content
+func TestFindContentInDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"episodes.xml": `<lsdvd><device>./episodes</device>
+<track><ix>1</ix><length>2580.0</length></track>
+<track><ix>2</ix><length>30.0</length></track>
+</lsdvd>`,
+		"extras.xml": `<lsdvd><device>./extras</device>
+<track><ix>1</ix><length>600.0</length></track>
+</lsdvd>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	results, err := FindContentInDir(dir, 43, 3)
+	if err != nil {
+		t.Fatalf("FindContentInDir failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected matches in 1 file, got %d: %v", len(results), results)
+	}
+	matches := results["episodes.xml"]
+	if len(matches) != 1 || matches[0].Type != "track" || matches[0].Track.Index != 1 {
+		t.Errorf("Expected track 1 of episodes.xml, got %+v", matches)
+	}
+
+	// Unparseable files are skipped but reported
+	if err := os.WriteFile(filepath.Join(dir, "empty.xml"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	results, err = FindContentInDir(dir, 43, 3)
+	if !errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("Expected ErrEmptyDocument, got %v", err)
+	}
+	if len(results["episodes.xml"]) != 1 {
+		t.Errorf("Expected matches despite the bad file, got %v", results)
+	}
+}
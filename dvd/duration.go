@@ -0,0 +1,42 @@
+package dvd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// FormatDuration formats a duration in seconds, rounded to the nearest
+// second, in the style "1h 23m 45s". Leading and trailing zero units are
+// omitted, so 2400 seconds is "40m" and 45 seconds is "45s"; zero is "0s".
+func FormatDuration(seconds float64) string {
+	sign := ""
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+
+	total := int64(math.Round(seconds))
+	if total == 0 {
+		return "0s"
+	}
+
+	values := []int64{total / 3600, total % 3600 / 60, total % 60}
+	units := []string{"h", "m", "s"}
+
+	first, last := -1, -1
+	for i, v := range values {
+		if v != 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+
+	parts := make([]string, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		parts = append(parts, fmt.Sprintf("%d%s", values[i], units[i]))
+	}
+	return sign + strings.Join(parts, " ")
+}
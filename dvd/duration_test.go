@@ -0,0 +1,29 @@
+package dvd
+
+import (
+	"testing"
+)
+
+// TestFormatDuration tests human-friendly duration formatting
+func TestFormatDuration(t *testing.T) {
+	testCases := []struct {
+		seconds  float64
+		expected string
+	}{
+		{45, "45s"},
+		{2400, "40m"},
+		{8940, "2h 29m"},
+		{5025, "1h 23m 45s"},
+		{3605, "1h 0m 5s"},
+		{2500.56, "41m 41s"},
+		{0, "0s"},
+		{0.4, "0s"},
+		{-90, "-1m 30s"},
+	}
+
+	for _, tc := range testCases {
+		if got := FormatDuration(tc.seconds); got != tc.expected {
+			t.Errorf("FormatDuration(%v) = %q, expected %q", tc.seconds, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package dvd
+
+// episodeLengthRatio is the smallest fraction of the longest episode's length
+// a track may have and still be considered an episode
+const episodeLengthRatio = 0.5
+
+// DetectEpisodes returns the tracks that appear to be episodes, in disc order.
+// Play-all tracks are excluded, and of the remaining tracks only those at
+// least half as long as the longest are kept, dropping menus, trailers and
+// other short extras.
+func (d *DVD) DetectEpisodes() []*Track {
+	var longest float64
+	for i := range d.Tracks {
+		if !d.isPlayAll(&d.Tracks[i]) && d.Tracks[i].Length > longest {
+			longest = d.Tracks[i].Length
+		}
+	}
+
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return t.Length > 0 && t.Length >= longest*episodeLengthRatio && !d.isPlayAll(t)
+	})
+}
+
+// MergeDiscs combines the episodes of several discs, such as the discs of a TV
+// season, into a single DVD. Episodes are found with DetectEpisodes and are
+// numbered sequentially across the discs, in the order given. Device, title
+// and the other disc-level fields come from the first disc, and LongestTrack
+// is set to the longest merged episode. Nil discs are skipped. The episodes
+// are deep copies, so the merged DVD shares no data with the source discs.
+func MergeDiscs(discs ...*DVD) *DVD {
+	merged := &DVD{}
+	first := true
+	for _, disc := range discs {
+		if disc == nil {
+			continue
+		}
+		if first {
+			*merged = *disc
+			merged.Tracks = nil
+			merged.LongestTrack = 0
+			first = false
+		}
+		for _, episode := range disc.DetectEpisodes() {
+			track := episode.clone()
+			track.Index = len(merged.Tracks) + 1
+			merged.Tracks = append(merged.Tracks, track)
+		}
+	}
+	if merged.Tracks == nil {
+		merged.Tracks = []Track{}
+	}
+
+	if longest := merged.GetTopNLongestTracks(1); len(longest) > 0 {
+		merged.LongestTrack = longest[0].Index
+	}
+	return merged
+}
@@ -0,0 +1,102 @@
+package dvd
+
+import (
+	"os"
+	"testing"
+)
+
+// newEpisodeDisc returns a disc with two episodes of the given lengths, a
+// play-all track combining them and a short menu track
+func newEpisodeDisc(device string, first, second float64) *DVD {
+	return &DVD{
+		Device:       device,
+		Title:        device,
+		LongestTrack: 1,
+		Tracks: []Track{
+			{Index: 1, VTS: 1, Length: first + second},
+			{Index: 2, VTS: 1, Length: first},
+			{Index: 3, VTS: 1, Length: second},
+			{Index: 4, VTS: 2, Length: 20},
+		},
+	}
+}
+
+// TestDetectEpisodes tests separating episodes from play-all and extra tracks
+func TestDetectEpisodes(t *testing.T) {
+	disc := newEpisodeDisc("disc1", 2500, 2450)
+	if got := trackIndexes(disc.DetectEpisodes()); !equalInts(got, []int{2, 3}) {
+		t.Errorf("Expected episodes [2 3], got %v", got)
+	}
+
+	if got := (&DVD{}).DetectEpisodes(); got == nil || len(got) != 0 {
+		t.Errorf("Expected no episodes on an empty DVD, got %v", got)
+	}
+
+	testFile := "../source/s1d1.xml"
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		t.Skipf("Test file %s not found, skipping fixture check", testFile)
+	}
+	fixture, err := ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", testFile, err)
+	}
+	if got := trackIndexes(fixture.DetectEpisodes()); !equalInts(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected episodes [1 2 3 4] in %s, got %v", testFile, got)
+	}
+}
+
+// TestMergeDiscs tests combining the episodes of several discs
+func TestMergeDiscs(t *testing.T) {
+	disc1 := newEpisodeDisc("disc1", 2500, 2450)
+	disc2 := newEpisodeDisc("disc2", 2400, 2600)
+
+	merged := MergeDiscs(disc1, nil, disc2)
+
+	if merged.Device != "disc1" || merged.Title != "disc1" {
+		t.Errorf("Expected device and title from the first disc, got %q and %q", merged.Device, merged.Title)
+	}
+
+	expectedLengths := []float64{2500, 2450, 2400, 2600}
+	if len(merged.Tracks) != len(expectedLengths) {
+		t.Fatalf("Expected %d tracks, got %d", len(expectedLengths), len(merged.Tracks))
+	}
+	for i, track := range merged.Tracks {
+		if track.Index != i+1 {
+			t.Errorf("Track %d: expected index %d, got %d", i, i+1, track.Index)
+		}
+		if track.Length != expectedLengths[i] {
+			t.Errorf("Track %d: expected length %.0f, got %.0f", i+1, expectedLengths[i], track.Length)
+		}
+	}
+	if merged.LongestTrack != 4 {
+		t.Errorf("Expected longest track 4, got %d", merged.LongestTrack)
+	}
+
+	// The source discs are not modified
+	if disc2.Tracks[1].Index != 2 || len(disc1.Tracks) != 4 {
+		t.Error("MergeDiscs modified the source discs")
+	}
+
+	// Changing the merged episodes does not change the source discs
+	disc1.Tracks[1].AudioStreams = []AudioStream{{Index: 1, Language: "English"}}
+	disc1.Tracks[1].SubtitleStreams = []SubtitleStream{{Index: 1, Language: "English"}}
+	disc1.Tracks[1].Chapters = []Chapter{{Index: 1, Length: 2500}}
+	disc1.Tracks[1].Cells = []Cell{{Index: 1, Length: 2500}}
+	disc1.Tracks[1].Palette.Colors = []string{"000000"}
+	merged = MergeDiscs(disc1)
+	episode := &merged.Tracks[0]
+	episode.AudioStreams[0].Language = "French"
+	episode.SubtitleStreams[0].Language = "French"
+	episode.Chapters[0].Length = 1
+	episode.Cells[0].Length = 1
+	episode.Palette.Colors[0] = "ffffff"
+	source := disc1.Tracks[1]
+	if source.AudioStreams[0].Language != "English" || source.SubtitleStreams[0].Language != "English" ||
+		source.Chapters[0].Length != 2500 || source.Cells[0].Length != 2500 || source.Palette.Colors[0] != "000000" {
+		t.Error("Changing a merged episode modified the source disc")
+	}
+
+	if empty := MergeDiscs(); empty.Tracks == nil || len(empty.Tracks) != 0 {
+		t.Errorf("Expected no tracks merging no discs, got %v", empty.Tracks)
+	}
+}
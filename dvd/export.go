@@ -0,0 +1,101 @@
+package dvd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// TracksCSVHeader is the header row written by WriteTracksCSV
+var TracksCSVHeader = []string{
+	"filename",
+	"device",
+	"track",
+	"length_seconds",
+	"resolution",
+	"format",
+	"fps",
+	"audio_streams",
+	"subtitle_streams",
+	"chapters",
+}
+
+// WriteTracksCSV writes one CSV row per track for each DVD in files, keyed by
+// filename. Files are written in sorted filename order, preceded by the
+// TracksCSVHeader row.
+func WriteTracksCSV(w io.Writer, files map[string]*DVD) error {
+	names := sortedFileNames(files)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(TracksCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %v", err)
+	}
+
+	for _, name := range names {
+		dvd := files[name]
+		if dvd == nil {
+			continue
+		}
+		for _, track := range dvd.Tracks {
+			record := []string{
+				name,
+				dvd.Device,
+				strconv.Itoa(track.Index),
+				strconv.FormatFloat(track.Length, 'f', 3, 64),
+				fmt.Sprintf("%dx%d", track.Width, track.Height),
+				track.Format,
+				strconv.FormatFloat(track.FPS, 'f', 2, 64),
+				strconv.Itoa(len(track.AudioStreams)),
+				strconv.Itoa(len(track.SubtitleStreams)),
+				strconv.Itoa(len(track.Chapters)),
+			}
+			if err := cw.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV row for %s track %d: %v", name, track.Index, err)
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %v", err)
+	}
+	return nil
+}
+
+// catalogEntry is one disc in the JSON catalog written by WriteCatalogJSON
+type catalogEntry struct {
+	File string
+	DVD  *DVD
+}
+
+// WriteCatalogJSON writes the DVDs in files as an indented JSON array of
+// objects holding the source filename and the parsed DVD, in sorted filename order
+func WriteCatalogJSON(w io.Writer, files map[string]*DVD) error {
+	entries := []catalogEntry{}
+	for _, name := range sortedFileNames(files) {
+		if files[name] == nil {
+			continue
+		}
+		entries = append(entries, catalogEntry{File: name, DVD: files[name]})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(entries); err != nil {
+		return fmt.Errorf("failed to write JSON catalog: %v", err)
+	}
+	return nil
+}
+
+// sortedFileNames returns the keys of files in sorted order
+func sortedFileNames(files map[string]*DVD) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,86 @@
+package dvd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestWriteTracksCSV tests the CSV track export
+func TestWriteTracksCSV(t *testing.T) {
+	dvd := &DVD{
+		Device: "./s1d1/Test, Disc",
+		Tracks: []Track{
+			{
+				Index:           1,
+				Length:          2500.56,
+				Width:           720,
+				Height:          576,
+				Format:          "PAL",
+				FPS:             25.0,
+				AudioStreams:    []AudioStream{{Index: 1}, {Index: 2}},
+				SubtitleStreams: []SubtitleStream{{Index: 1}},
+				Chapters:        []Chapter{{Index: 1}, {Index: 2}, {Index: 3}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTracksCSV(&buf, map[string]*DVD{"s1d1.xml": dvd}); err != nil {
+		t.Fatalf("WriteTracksCSV failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	expectedHeader := "filename,device,track,length_seconds,resolution,format,fps,audio_streams,subtitle_streams,chapters"
+	if lines[0] != expectedHeader {
+		t.Errorf("Expected header %q, got %q", expectedHeader, lines[0])
+	}
+
+	expectedRow := `s1d1.xml,"./s1d1/Test, Disc",1,2500.560,720x576,PAL,25.00,2,1,3`
+	if lines[1] != expectedRow {
+		t.Errorf("Expected row %q, got %q", expectedRow, lines[1])
+	}
+}
+
+// TestWriteCatalogJSON tests the JSON catalog export
+func TestWriteCatalogJSON(t *testing.T) {
+	files := map[string]*DVD{
+		"s1d2.xml": {Device: "./s1d2", Tracks: []Track{{Index: 1, Length: 2500}}},
+		"s1d1.xml": {Device: "./s1d1", Tracks: []Track{{Index: 1, Length: 2400}, {Index: 2, Length: 30}}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCatalogJSON(&buf, files); err != nil {
+		t.Fatalf("WriteCatalogJSON failed: %v", err)
+	}
+
+	var catalog []struct {
+		File string
+		DVD  DVD
+	}
+	if err := json.Unmarshal(buf.Bytes(), &catalog); err != nil {
+		t.Fatalf("Output is not a valid JSON array: %v\n%s", err, buf.String())
+	}
+	if len(catalog) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(catalog))
+	}
+	if catalog[0].File != "s1d1.xml" || catalog[1].File != "s1d2.xml" {
+		t.Errorf("Expected entries sorted by filename, got %q and %q", catalog[0].File, catalog[1].File)
+	}
+	if catalog[0].DVD.Device != "./s1d1" || len(catalog[0].DVD.Tracks) != 2 {
+		t.Errorf("Expected s1d1.xml entry with 2 tracks, got %+v", catalog[0].DVD)
+	}
+
+	buf.Reset()
+	if err := WriteCatalogJSON(&buf, nil); err != nil {
+		t.Fatalf("WriteCatalogJSON failed: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Expected empty array for no files, got %q", buf.String())
+	}
+}
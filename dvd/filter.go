@@ -0,0 +1,434 @@
+package dvd
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// trackPointers returns pointers to every track of the DVD, in disc order
+func (d *DVD) trackPointers() []*Track {
+	tracks := make([]*Track, len(d.Tracks))
+	for i := range d.Tracks {
+		tracks[i] = &d.Tracks[i]
+	}
+	return tracks
+}
+
+// sortedTracks returns pointers to the tracks sorted by key, ascending or
+// descending. Tracks with equal keys are ordered by index. The DVD's tracks
+// are not modified.
+func (d *DVD) sortedTracks(key func(*Track) float64, ascending bool) []*Track {
+	tracks := d.trackPointers()
+	sort.SliceStable(tracks, func(i, j int) bool {
+		ki, kj := key(tracks[i]), key(tracks[j])
+		if ki != kj {
+			return (ki < kj) == ascending
+		}
+		return tracks[i].Index < tracks[j].Index
+	})
+	return tracks
+}
+
+// trackLength returns the length of a track, for use as a sort key
+func trackLength(t *Track) float64 {
+	return t.Length
+}
+
+// SortTracksByDuration returns the tracks sorted by length, shortest first
+// when ascending is set and longest first otherwise. Tracks of equal length
+// are ordered by index. The DVD's tracks are not modified.
+func (d *DVD) SortTracksByDuration(ascending bool) []*Track {
+	return d.sortedTracks(trackLength, ascending)
+}
+
+// SortTracksByChapterCount returns the tracks sorted by number of chapters,
+// fewest first when ascending is set and most first otherwise. Tracks with the
+// same number of chapters are ordered by index. The DVD's tracks are not modified.
+func (d *DVD) SortTracksByChapterCount(ascending bool) []*Track {
+	return d.sortedTracks(func(t *Track) float64 {
+		return float64(len(t.Chapters))
+	}, ascending)
+}
+
+// SortTracksByIndex sorts the DVD's tracks in place by index, for lsdvd output
+// that lists tracks out of order. LongestTrack holds a track index rather than
+// a slice position, so it is unaffected.
+func (d *DVD) SortTracksByIndex() {
+	sort.SliceStable(d.Tracks, func(i, j int) bool {
+		return d.Tracks[i].Index < d.Tracks[j].Index
+	})
+}
+
+// GetTopNLongestTracks returns up to n tracks sorted by length, longest first.
+// Tracks of equal length are ordered by index. The DVD's tracks are not modified.
+func (d *DVD) GetTopNLongestTracks(n int) []*Track {
+	if n <= 0 {
+		return []*Track{}
+	}
+	tracks := d.SortTracksByDuration(false)
+	if n < len(tracks) {
+		tracks = tracks[:n]
+	}
+	return tracks
+}
+
+// GetTopNShortestTracks returns up to n tracks sorted by length, shortest first.
+// Tracks of equal length are ordered by index. The DVD's tracks are not modified.
+func (d *DVD) GetTopNShortestTracks(n int) []*Track {
+	if n <= 0 {
+		return []*Track{}
+	}
+	tracks := d.SortTracksByDuration(true)
+	if n < len(tracks) {
+		tracks = tracks[:n]
+	}
+	return tracks
+}
+
+// FilterTracksFunc returns the tracks for which pred returns true, in disc
+// order. A nil pred matches every track. The returned pointers refer to the
+// DVD's own tracks, so changes made through them modify the DVD.
+func (d *DVD) FilterTracksFunc(pred func(*Track) bool) []*Track {
+	tracks := []*Track{}
+	for i := range d.Tracks {
+		if pred == nil || pred(&d.Tracks[i]) {
+			tracks = append(tracks, &d.Tracks[i])
+		}
+	}
+	return tracks
+}
+
+// GetTracksLongerThan returns the tracks with a length strictly greater than seconds
+func (d *DVD) GetTracksLongerThan(seconds float64) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return t.Length > seconds
+	})
+}
+
+// GetTracksShorterThan returns the tracks with a length strictly less than seconds
+func (d *DVD) GetTracksShorterThan(seconds float64) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return t.Length < seconds
+	})
+}
+
+// GetTracksInDurationRange returns the tracks with a length between minSeconds
+// and maxSeconds inclusive. An inverted range returns no tracks.
+func (d *DVD) GetTracksInDurationRange(minSeconds, maxSeconds float64) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return t.Length >= minSeconds && t.Length <= maxSeconds
+	})
+}
+
+// GetTracksNearDuration returns all tracks sorted by how close their length is
+// to targetSeconds, closest first. Tracks equally close are ordered by index.
+func (d *DVD) GetTracksNearDuration(targetSeconds float64) []*Track {
+	tracks := d.trackPointers()
+	sort.SliceStable(tracks, func(i, j int) bool {
+		di := math.Abs(tracks[i].Length - targetSeconds)
+		dj := math.Abs(tracks[j].Length - targetSeconds)
+		if di != dj {
+			return di < dj
+		}
+		return tracks[i].Index < tracks[j].Index
+	})
+	return tracks
+}
+
+// GetTracksByFormat returns the tracks with the given video format (e.g. "PAL"
+// or "NTSC"), compared case-insensitively
+func (d *DVD) GetTracksByFormat(format string) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return strings.EqualFold(t.Format, format)
+	})
+}
+
+// GetTracksByFPS returns the tracks whose frame rate is within tolerance of fps.
+// A negative tolerance returns no tracks.
+func (d *DVD) GetTracksByFPS(fps, tolerance float64) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return math.Abs(t.FPS-fps) <= tolerance
+	})
+}
+
+// GetTracksByResolution returns the tracks with the given width and height. A
+// zero width or height matches any value for that dimension.
+func (d *DVD) GetTracksByResolution(width, height int) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return (width == 0 || t.Width == width) && (height == 0 || t.Height == height)
+	})
+}
+
+// GetTracksByAspect returns the tracks whose aspect string exactly matches
+// aspect (e.g. "4/3" or "16/9")
+func (d *DVD) GetTracksByAspect(aspect string) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return t.Aspect == aspect
+	})
+}
+
+// GetTracksByDF returns the tracks whose display format (e.g. "Pan&Scan" or
+// "Letterbox") matches df, compared case-insensitively against the decoded value
+func (d *DVD) GetTracksByDF(df string) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return strings.EqualFold(t.DF, df)
+	})
+}
+
+// GetTracksByVTSID returns the tracks whose VTS identifier exactly matches vtsID
+func (d *DVD) GetTracksByVTSID(vtsID string) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return t.VTSID == vtsID
+	})
+}
+
+// FindTracksMatchingAll returns the tracks that satisfy every predicate. With
+// no predicates, all tracks are returned.
+func (d *DVD) FindTracksMatchingAll(preds ...func(*Track) bool) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		for _, pred := range preds {
+			if !pred(t) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// FindTracksMatchingAny returns the tracks that satisfy at least one
+// predicate, each track appearing once. With no predicates, no tracks are returned.
+func (d *DVD) FindTracksMatchingAny(preds ...func(*Track) bool) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		for _, pred := range preds {
+			if pred(t) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// duplicateLengthTolerance is the largest length difference in seconds for
+// two tracks to be considered duplicates
+const duplicateLengthTolerance = 1.0
+
+// UniqueTracks returns copies of the DVD's tracks with duplicates removed.
+// Tracks are duplicates when their lengths are within a second of each other
+// and they have the same resolution, frame rate, and audio and subtitle
+// languages. Of each set of duplicates, the track with the lowest index is
+// kept. The result is ordered by track index.
+func (d *DVD) UniqueTracks() []Track {
+	tracks := d.trackPointers()
+	sort.SliceStable(tracks, func(i, j int) bool {
+		return tracks[i].Index < tracks[j].Index
+	})
+
+	unique := []Track{}
+	for _, track := range tracks {
+		duplicate := false
+		for i := range unique {
+			if isDuplicateTrack(&unique[i], track) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			unique = append(unique, *track)
+		}
+	}
+	return unique
+}
+
+// isDuplicateTrack reports whether two tracks appear to be the same title
+func isDuplicateTrack(a, b *Track) bool {
+	return math.Abs(a.Length-b.Length) <= duplicateLengthTolerance &&
+		a.Width == b.Width && a.Height == b.Height && a.FPS == b.FPS &&
+		sameStringSet(a.AudioLanguages(), b.AudioLanguages()) &&
+		sameStringSet(a.SubtitleLanguages(), b.SubtitleLanguages())
+}
+
+// sameStringSet reports whether two slices of unique strings hold the same values
+func sameStringSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		if !set[s] {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyFilter returns a copy of the DVD containing only the tracks for which
+// pred returns true. LongestTrack is set to the index of the longest remaining
+// track, or 0 if none remain. The original DVD is not modified.
+func (d *DVD) ApplyFilter(pred func(*Track) bool) *DVD {
+	filtered := *d
+	filtered.Tracks = []Track{}
+	filtered.LongestTrack = 0
+
+	var longest float64
+	for _, track := range d.FilterTracksFunc(pred) {
+		filtered.Tracks = append(filtered.Tracks, *track)
+		if filtered.LongestTrack == 0 || track.Length > longest {
+			filtered.LongestTrack = track.Index
+			longest = track.Length
+		}
+	}
+	return &filtered
+}
+
+// Partition splits the tracks into those for which pred returns true and the
+// rest, in a single pass. Every track appears in exactly one of the results.
+// A nil pred matches every track, like FilterTracksFunc.
+func (d *DVD) Partition(pred func(*Track) bool) (matched, rest []*Track) {
+	matched = []*Track{}
+	rest = []*Track{}
+	for i := range d.Tracks {
+		if pred == nil || pred(&d.Tracks[i]) {
+			matched = append(matched, &d.Tracks[i])
+		} else {
+			rest = append(rest, &d.Tracks[i])
+		}
+	}
+	return matched, rest
+}
+
+// GetTracksWithLanguage returns the tracks with at least one audio stream in
+// the given language, compared case-insensitively against the language name
+func (d *DVD) GetTracksWithLanguage(language string) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return hasAudioLanguage(t, language)
+	})
+}
+
+// GetTracksWithSubtitleLanguage returns the tracks with at least one subtitle
+// stream in the given language, compared case-insensitively against the language name
+func (d *DVD) GetTracksWithSubtitleLanguage(language string) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return hasSubtitleLanguage(t, language)
+	})
+}
+
+// GetTracksWithBoth returns the tracks that have an audio stream in audioLang
+// and a subtitle stream in subLang, both compared case-insensitively
+func (d *DVD) GetTracksWithBoth(audioLang, subLang string) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return hasAudioLanguage(t, audioLang) && hasSubtitleLanguage(t, subLang)
+	})
+}
+
+// hasAudioLanguage reports whether the track has an audio stream in language
+func hasAudioLanguage(t *Track, language string) bool {
+	for _, audio := range t.AudioStreams {
+		if strings.EqualFold(audio.Language, language) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSubtitleLanguage reports whether the track has a subtitle stream in language
+func hasSubtitleLanguage(t *Track, language string) bool {
+	for _, sub := range t.SubtitleStreams {
+		if strings.EqualFold(sub.Language, language) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAudioFormat reports whether the track has an audio stream in format
+func hasAudioFormat(t *Track, format string) bool {
+	for _, audio := range t.AudioStreams {
+		if strings.EqualFold(audio.Format, format) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTracksWithNAudioStreams returns the tracks with exactly n audio streams
+func (d *DVD) GetTracksWithNAudioStreams(n int) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return len(t.AudioStreams) == n
+	})
+}
+
+// GetTracksWithMinChapters returns the tracks with at least n chapters
+func (d *DVD) GetTracksWithMinChapters(n int) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return len(t.Chapters) >= n
+	})
+}
+
+// GetTracksWithoutChapters returns the tracks that have no chapters
+func (d *DVD) GetTracksWithoutChapters() []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return len(t.Chapters) == 0
+	})
+}
+
+// GetTracksWithMultiAngle returns the tracks that offer more than one camera angle
+func (d *DVD) GetTracksWithMultiAngle() []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return t.IsMultiAngle()
+	})
+}
+
+// MinLength returns a predicate, for use with FilterTracksFunc and ApplyFilter,
+// that matches tracks with a length of at least seconds
+func MinLength(seconds float64) func(*Track) bool {
+	return func(t *Track) bool {
+		return t.Length >= seconds
+	}
+}
+
+// GetTracksWithSurroundSound returns the tracks with at least one audio stream
+// of more than two channels. Streams with an unknown channel count are ignored.
+func (d *DVD) GetTracksWithSurroundSound() []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		for i := range t.AudioStreams {
+			if t.AudioStreams[i].IsSurroundSound() {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// GetTracksWithDolbyDigital returns the tracks with at least one Dolby Digital
+// audio stream, which lsdvd reports as "ac3"
+func (d *DVD) GetTracksWithDolbyDigital() []*Track {
+	return d.GetTracksWithAudioFormat("ac3")
+}
+
+// GetTracksWithDTS returns the tracks with at least one DTS audio stream
+func (d *DVD) GetTracksWithDTS() []*Track {
+	return d.GetTracksWithAudioFormat("dts")
+}
+
+// GetTracksWithAudioFormat returns the tracks with at least one audio stream in
+// the given format (e.g. "ac3", "dts", "mp2" or "lpcm"), compared case-insensitively
+func (d *DVD) GetTracksWithAudioFormat(format string) []*Track {
+	return d.FilterTracksFunc(func(t *Track) bool {
+		return hasAudioFormat(t, format)
+	})
+}
+
+// TracksByVTS returns copies of the DVD's tracks grouped by title set number
+// (VTS), each group in disc order. Tracks in the same title set are often
+// related, such as the episodes of a TV disc.
+func (d *DVD) TracksByVTS() map[int][]Track {
+	groups := make(map[int][]Track)
+	for _, track := range d.Tracks {
+		groups[track.VTS] = append(groups[track.VTS], track)
+	}
+	return groups
+}
@@ -0,0 +1,870 @@
+package dvd
+
+import (
+	"math"
+	"os"
+	"testing"
+)
+
+// newFilterTestDVD returns a DVD with a mix of track lengths for filter tests
+func newFilterTestDVD() *DVD {
+	return &DVD{Tracks: []Track{
+		{Index: 1, Length: 2500.0},
+		{Index: 2, Length: 120.0},
+		{Index: 3, Length: 2500.0},
+		{Index: 4, Length: 9800.0},
+		{Index: 5, Length: 30.0},
+	}}
+}
+
+// trackIndexes returns the index of each track, for comparing results
+func trackIndexes(tracks []*Track) []int {
+	indexes := make([]int, len(tracks))
+	for i, track := range tracks {
+		indexes[i] = track.Index
+	}
+	return indexes
+}
+
+// equalInts reports whether two int slices have the same elements in order
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestGetTopNTracks tests ranked track access by length
+func TestGetTopNTracks(t *testing.T) {
+	dvd := newFilterTestDVD()
+	original := trackIndexes(dvd.trackPointers())
+
+	testCases := []struct {
+		name     string
+		tracks   []*Track
+		expected []int
+	}{
+		{"longest 3", dvd.GetTopNLongestTracks(3), []int{4, 1, 3}},
+		{"longest all", dvd.GetTopNLongestTracks(10), []int{4, 1, 3, 2, 5}},
+		{"longest 0", dvd.GetTopNLongestTracks(0), []int{}},
+		{"shortest 2", dvd.GetTopNShortestTracks(2), []int{5, 2}},
+		{"shortest all", dvd.GetTopNShortestTracks(10), []int{5, 2, 1, 3, 4}},
+		{"shortest 0", dvd.GetTopNShortestTracks(0), []int{}},
+	}
+
+	for _, tc := range testCases {
+		if tc.tracks == nil {
+			t.Errorf("%s: expected non-nil slice", tc.name)
+		}
+		if got := trackIndexes(tc.tracks); !equalInts(got, tc.expected) {
+			t.Errorf("%s: expected tracks %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+
+	if got := trackIndexes(dvd.trackPointers()); !equalInts(got, original) {
+		t.Errorf("Tracks were reordered: expected %v, got %v", original, got)
+	}
+}
+
+// TestGetTracksLongerShorterThan tests threshold-based filtering
+func TestGetTracksLongerShorterThan(t *testing.T) {
+	dvd := newFilterTestDVD()
+	dvd.Tracks = append(dvd.Tracks, Track{Index: 6, Length: 0})
+
+	testCases := []struct {
+		name     string
+		tracks   []*Track
+		expected []int
+	}{
+		{"longer than 2500", dvd.GetTracksLongerThan(2500.0), []int{4}},
+		{"shorter than 2500", dvd.GetTracksShorterThan(2500.0), []int{2, 5, 6}},
+		{"longer than 0", dvd.GetTracksLongerThan(0), []int{1, 2, 3, 4, 5}},
+		{"shorter than 0", dvd.GetTracksShorterThan(0), []int{}},
+	}
+
+	for _, tc := range testCases {
+		if got := trackIndexes(tc.tracks); !equalInts(got, tc.expected) {
+			t.Errorf("%s: expected tracks %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+// TestGetTracksInDurationRange tests inclusive range filtering
+func TestGetTracksInDurationRange(t *testing.T) {
+	dvd := newFilterTestDVD()
+
+	testCases := []struct {
+		name     string
+		min, max float64
+		expected []int
+	}{
+		{"inclusive bounds", 120.0, 2500.0, []int{1, 2, 3}},
+		{"single value", 2500.0, 2500.0, []int{1, 3}},
+		{"inverted", 2500.0, 120.0, []int{}},
+		{"no matches", 3000.0, 4000.0, []int{}},
+	}
+
+	for _, tc := range testCases {
+		got := trackIndexes(dvd.GetTracksInDurationRange(tc.min, tc.max))
+		if !equalInts(got, tc.expected) {
+			t.Errorf("%s: expected tracks %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+// TestGetTracksNearDuration tests ordering tracks by proximity to a target
+func TestGetTracksNearDuration(t *testing.T) {
+	dvd := newFilterTestDVD()
+	dvd.Tracks = append(dvd.Tracks, Track{Index: 6, Length: 2400.0}, Track{Index: 7, Length: 2600.0})
+
+	tracks := dvd.GetTracksNearDuration(2500.0)
+	if len(tracks) != len(dvd.Tracks) {
+		t.Fatalf("Expected all %d tracks, got %d", len(dvd.Tracks), len(tracks))
+	}
+
+	if tracks[0].Length != 2500.0 {
+		t.Errorf("Expected closest track to have length 2500.0, got %.1f", tracks[0].Length)
+	}
+
+	// Tracks 1 and 3 are exact matches and tracks 6 and 7 are both 100
+	// seconds away, so each pair is ordered by index
+	expected := []int{1, 3, 6, 7, 2, 5, 4}
+	if got := trackIndexes(tracks); !equalInts(got, expected) {
+		t.Errorf("Expected tracks %v, got %v", expected, got)
+	}
+}
+
+// TestGetTracksByFormat tests filtering tracks by video format
+func TestGetTracksByFormat(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Format: "PAL"},
+		{Index: 2, Format: "NTSC"},
+		{Index: 3, Format: "PAL"},
+	}}
+
+	if got := trackIndexes(dvd.GetTracksByFormat("pal")); !equalInts(got, []int{1, 3}) {
+		t.Errorf("Expected PAL tracks [1 3], got %v", got)
+	}
+	if got := trackIndexes(dvd.GetTracksByFormat("NTSC")); !equalInts(got, []int{2}) {
+		t.Errorf("Expected NTSC tracks [2], got %v", got)
+	}
+	if tracks := dvd.GetTracksByFormat("SECAM"); tracks == nil || len(tracks) != 0 {
+		t.Errorf("Expected empty slice for unmatched format, got %v", trackIndexes(tracks))
+	}
+
+	testFile := "../source/s1d1.xml"
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		t.Skipf("Test file %s not found, skipping fixture check", testFile)
+	}
+	fixture, err := ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", testFile, err)
+	}
+	if tracks := fixture.GetTracksByFormat("PAL"); len(tracks) != 10 {
+		t.Errorf("Expected 10 PAL tracks in %s, got %d", testFile, len(tracks))
+	}
+}
+
+// TestGetTracksByFPS tests frame rate filtering with a tolerance
+func TestGetTracksByFPS(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, FPS: 25.0},
+		{Index: 2, FPS: 24000.0 / 1001.0},
+		{Index: 3, FPS: 30000.0 / 1001.0},
+		{Index: 4, FPS: 25.000001},
+	}}
+
+	testCases := []struct {
+		name      string
+		fps       float64
+		tolerance float64
+		expected  []int
+	}{
+		{"film rate with tolerance", 23.976, 0.01, []int{2}},
+		{"film rate strict", 23.976, 0.0, []int{}},
+		{"PAL with tolerance", 25.0, 0.001, []int{1, 4}},
+		{"PAL strict", 25.0, 0.0, []int{1}},
+		{"negative tolerance", 25.0, -1.0, []int{}},
+	}
+
+	for _, tc := range testCases {
+		got := trackIndexes(dvd.GetTracksByFPS(tc.fps, tc.tolerance))
+		if !equalInts(got, tc.expected) {
+			t.Errorf("%s: expected tracks %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+// TestGetTracksByResolution tests resolution filtering with wildcards
+func TestGetTracksByResolution(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Width: 720, Height: 576},
+		{Index: 2, Width: 720, Height: 480},
+		{Index: 3, Width: 352, Height: 576},
+	}}
+
+	testCases := []struct {
+		name          string
+		width, height int
+		expected      []int
+	}{
+		{"exact match", 720, 576, []int{1}},
+		{"wildcard width", 0, 576, []int{1, 3}},
+		{"wildcard height", 720, 0, []int{1, 2}},
+		{"both wildcards", 0, 0, []int{1, 2, 3}},
+		{"no match", 1920, 1080, []int{}},
+	}
+
+	for _, tc := range testCases {
+		got := trackIndexes(dvd.GetTracksByResolution(tc.width, tc.height))
+		if !equalInts(got, tc.expected) {
+			t.Errorf("%s: expected tracks %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+// TestGetTracksByAspect tests aspect ratio filtering
+func TestGetTracksByAspect(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Aspect: "4/3"},
+		{Index: 2, Aspect: "16/9"},
+		{Index: 3, Aspect: ""},
+	}}
+
+	if got := trackIndexes(dvd.GetTracksByAspect("16/9")); !equalInts(got, []int{2}) {
+		t.Errorf("Expected 16/9 tracks [2], got %v", got)
+	}
+	if got := trackIndexes(dvd.GetTracksByAspect("")); !equalInts(got, []int{3}) {
+		t.Errorf("Expected tracks without aspect [3], got %v", got)
+	}
+
+	testFile := "../source/s1d1.xml"
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		t.Skipf("Test file %s not found, skipping fixture check", testFile)
+	}
+	fixture, err := ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", testFile, err)
+	}
+	// s1d1.xml has a single 16/9 track among its ten tracks
+	if tracks := fixture.GetTracksByAspect("4/3"); len(tracks) != 9 {
+		t.Errorf("Expected 9 4/3 tracks in %s, got %d", testFile, len(tracks))
+	}
+	if got := trackIndexes(fixture.GetTracksByAspect("16/9")); !equalInts(got, []int{6}) {
+		t.Errorf("Expected 16/9 tracks [6] in %s, got %v", testFile, got)
+	}
+}
+
+// TestGetTracksByDF tests display format filtering on entity-fixed values
+func TestGetTracksByDF(t *testing.T) {
+	xmlData := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<lsdvd>
+    <device>./test</device>
+    <track>
+        <ix>1</ix>
+        <df>Pan&Scan</df>
+    </track>
+    <track>
+        <ix>2</ix>
+        <df>Letterbox</df>
+    </track>
+    <track>
+        <ix>3</ix>
+        <df>Pan&Scan</df>
+    </track>
+    <longest_track>1</longest_track>
+</lsdvd>`)
+
+	dvd, err := ParseBytes(xmlData)
+	if err != nil {
+		t.Fatalf("Failed to parse XML: %v", err)
+	}
+
+	if got := trackIndexes(dvd.GetTracksByDF("pan&scan")); !equalInts(got, []int{1, 3}) {
+		t.Errorf("Expected Pan&Scan tracks [1 3], got %v", got)
+	}
+	if got := trackIndexes(dvd.GetTracksByDF("Letterbox")); !equalInts(got, []int{2}) {
+		t.Errorf("Expected Letterbox tracks [2], got %v", got)
+	}
+	if got := trackIndexes(dvd.GetTracksByDF("Pan&amp;Scan")); !equalInts(got, []int{}) {
+		t.Errorf("Expected no tracks for encoded value, got %v", got)
+	}
+}
+
+// TestGetTracksByVTSID tests filtering by VTS identifier
+func TestGetTracksByVTSID(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, VTSID: "DVDVIDEO-VTS"},
+		{Index: 2, VTSID: "DVDVIDEO-VTS2"},
+		{Index: 3, VTSID: "DVDVIDEO-VTS"},
+		{Index: 4},
+	}}
+
+	testCases := []struct {
+		vtsID    string
+		expected []int
+	}{
+		{"DVDVIDEO-VTS", []int{1, 3}},
+		{"DVDVIDEO-VTS2", []int{2}},
+		{"dvdvideo-vts", []int{}},
+		{"", []int{4}},
+	}
+
+	for _, tc := range testCases {
+		got := trackIndexes(dvd.GetTracksByVTSID(tc.vtsID))
+		if !equalInts(got, tc.expected) {
+			t.Errorf("GetTracksByVTSID(%q): expected tracks %v, got %v", tc.vtsID, tc.expected, got)
+		}
+	}
+}
+
+// TestFilterTracksFunc tests predicate-based track filtering
+func TestFilterTracksFunc(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Format: "PAL", AudioStreams: []AudioStream{{Index: 1}, {Index: 2}}},
+		{Index: 2, Format: "PAL", AudioStreams: []AudioStream{{Index: 1}}},
+		{Index: 3, Format: "NTSC", AudioStreams: []AudioStream{{Index: 1}, {Index: 2}}},
+		{Index: 4, Format: "PAL", AudioStreams: []AudioStream{{Index: 1}, {Index: 2}, {Index: 3}}},
+	}}
+
+	tracks := dvd.FilterTracksFunc(func(t *Track) bool {
+		return t.Format == "PAL" && len(t.AudioStreams) > 1
+	})
+	if got := trackIndexes(tracks); !equalInts(got, []int{1, 4}) {
+		t.Errorf("Expected tracks [1 4], got %v", got)
+	}
+
+	// Returned pointers refer to the original tracks
+	tracks[0].Aspect = "16/9"
+	if dvd.Tracks[0].Aspect != "16/9" {
+		t.Error("Expected FilterTracksFunc to return pointers into the DVD's tracks")
+	}
+
+	if got := trackIndexes(dvd.FilterTracksFunc(nil)); !equalInts(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected nil predicate to return all tracks, got %v", got)
+	}
+}
+
+// TestFindTracksMatchingAll tests combining predicates with AND
+func TestFindTracksMatchingAll(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Format: "PAL", Length: 2500.0},
+		{Index: 2, Format: "PAL", Length: 120.0},
+		{Index: 3, Format: "NTSC", Length: 2500.0},
+	}}
+
+	isPAL := func(t *Track) bool { return t.Format == "PAL" }
+	atLeast20Min := func(t *Track) bool { return t.Length >= 1200.0 }
+	never := func(t *Track) bool { return false }
+
+	if got := trackIndexes(dvd.FindTracksMatchingAll(isPAL, atLeast20Min)); !equalInts(got, []int{1}) {
+		t.Errorf("Expected tracks [1], got %v", got)
+	}
+	if got := trackIndexes(dvd.FindTracksMatchingAll(isPAL, atLeast20Min, never)); !equalInts(got, []int{}) {
+		t.Errorf("Expected no tracks with a failing predicate, got %v", got)
+	}
+	if got := trackIndexes(dvd.FindTracksMatchingAll()); !equalInts(got, []int{1, 2, 3}) {
+		t.Errorf("Expected all tracks with no predicates, got %v", got)
+	}
+}
+
+// TestFindTracksMatchingAny tests combining predicates with OR
+func TestFindTracksMatchingAny(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Format: "PAL", FPS: 25.0},
+		{Index: 2, Format: "NTSC", FPS: 23.976},
+		{Index: 3, Format: "PAL", FPS: 24.0},
+		{Index: 4, Format: "NTSC", FPS: 29.97},
+	}}
+
+	isPAL := func(t *Track) bool { return t.Format == "PAL" }
+	isFilm := func(t *Track) bool { return math.Abs(t.FPS-24.0) < 0.1 }
+
+	// Track 3 satisfies both predicates but appears once
+	if got := trackIndexes(dvd.FindTracksMatchingAny(isPAL, isFilm)); !equalInts(got, []int{1, 2, 3}) {
+		t.Errorf("Expected tracks [1 2 3], got %v", got)
+	}
+	if got := trackIndexes(dvd.FindTracksMatchingAny()); !equalInts(got, []int{}) {
+		t.Errorf("Expected no tracks with no predicates, got %v", got)
+	}
+}
+
+// TestUniqueTracks tests collapsing duplicate tracks
+func TestUniqueTracks(t *testing.T) {
+	audio := []AudioStream{{Index: 1, Language: "English"}, {Index: 2, Language: "Francais"}}
+	subs := []SubtitleStream{{Index: 1, Language: "Nederlands"}}
+
+	dvd := &DVD{Tracks: []Track{
+		{Index: 2, Length: 2500.9, Width: 720, Height: 576, FPS: 25.0, AudioStreams: audio, SubtitleStreams: subs},
+		{Index: 1, Length: 2500.2, Width: 720, Height: 576, FPS: 25.0, AudioStreams: audio, SubtitleStreams: subs},
+		{Index: 3, Length: 2500.5, Width: 720, Height: 576, FPS: 25.0, AudioStreams: audio[:1], SubtitleStreams: subs},
+	}}
+
+	unique := dvd.UniqueTracks()
+	if len(unique) != 2 {
+		t.Fatalf("Expected 2 unique tracks, got %d", len(unique))
+	}
+	if unique[0].Index != 1 || unique[1].Index != 3 {
+		t.Errorf("Expected tracks 1 and 3 to survive, got %d and %d", unique[0].Index, unique[1].Index)
+	}
+	if len(dvd.Tracks) != 3 {
+		t.Errorf("Expected the DVD to keep its 3 tracks, got %d", len(dvd.Tracks))
+	}
+}
+
+// TestApplyFilter tests building a filtered copy of a DVD
+func TestApplyFilter(t *testing.T) {
+	dvd := newFilterTestDVD()
+	dvd.Device = "./s1d1/Law And Order Svu"
+	dvd.Title = "unknown"
+	dvd.VMGID = "DVDVIDEO-VMG"
+	dvd.ProviderID = "TEST"
+	dvd.LongestTrack = 4
+
+	filtered := dvd.ApplyFilter(func(t *Track) bool {
+		return t.Length < 5000.0
+	})
+
+	if filtered.Device != dvd.Device || filtered.Title != dvd.Title ||
+		filtered.VMGID != dvd.VMGID || filtered.ProviderID != dvd.ProviderID {
+		t.Errorf("Expected disc fields to be preserved, got %+v", filtered)
+	}
+
+	var indexes []int
+	for _, track := range filtered.Tracks {
+		indexes = append(indexes, track.Index)
+	}
+	if !equalInts(indexes, []int{1, 2, 3, 5}) {
+		t.Errorf("Expected tracks [1 2 3 5], got %v", indexes)
+	}
+
+	// Tracks 1 and 3 are equally long, so the first one wins
+	if filtered.LongestTrack != 1 {
+		t.Errorf("Expected longest track 1, got %d", filtered.LongestTrack)
+	}
+	if track := filtered.GetTrackByIndex(filtered.LongestTrack); track == nil || track.Length != 2500.0 {
+		t.Errorf("Expected LongestTrack to refer to a 2500 second track, got %+v", track)
+	}
+
+	if len(dvd.Tracks) != 5 || dvd.LongestTrack != 4 {
+		t.Errorf("Expected original DVD to be unchanged, got %d tracks, longest %d", len(dvd.Tracks), dvd.LongestTrack)
+	}
+
+	// Modifying the filtered copy doesn't affect the original
+	filtered.Tracks[0].Length = 1.0
+	if dvd.Tracks[0].Length != 2500.0 {
+		t.Error("Expected filtered tracks to be copies")
+	}
+
+	empty := dvd.ApplyFilter(func(t *Track) bool { return false })
+	if len(empty.Tracks) != 0 || empty.LongestTrack != 0 {
+		t.Errorf("Expected no tracks and longest track 0, got %d tracks, longest %d", len(empty.Tracks), empty.LongestTrack)
+	}
+}
+
+// TestPartition tests splitting tracks into two groups
+func TestPartition(t *testing.T) {
+	dvd := newFilterTestDVD()
+
+	isLong := func(t *Track) bool { return t.Length >= 1200.0 }
+	matched, rest := dvd.Partition(isLong)
+	if len(matched)+len(rest) != len(dvd.Tracks) {
+		t.Errorf("Expected %d tracks in total, got %d", len(dvd.Tracks), len(matched)+len(rest))
+	}
+	if got := trackIndexes(matched); !equalInts(got, []int{1, 3, 4}) {
+		t.Errorf("Expected matched tracks [1 3 4], got %v", got)
+	}
+	if got := trackIndexes(rest); !equalInts(got, []int{2, 5}) {
+		t.Errorf("Expected remaining tracks [2 5], got %v", got)
+	}
+	for _, track := range matched {
+		if !isLong(track) {
+			t.Errorf("Track %d should not be matched", track.Index)
+		}
+	}
+	for _, track := range rest {
+		if isLong(track) {
+			t.Errorf("Track %d should be matched", track.Index)
+		}
+	}
+
+	matched, rest = dvd.Partition(func(t *Track) bool { return false })
+	if len(matched) != 0 || len(rest) != len(dvd.Tracks) {
+		t.Errorf("Expected no matched and %d remaining tracks, got %d and %d",
+			len(dvd.Tracks), len(matched), len(rest))
+	}
+
+	// A nil predicate matches every track
+	matched, rest = dvd.Partition(nil)
+	if got := trackIndexes(matched); !equalInts(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected nil predicate to match all tracks, got %v", got)
+	}
+	if rest == nil || len(rest) != 0 {
+		t.Errorf("Expected an empty, non-nil rest for nil predicate, got %v", rest)
+	}
+}
+
+// TestSortTracksByDuration tests sorting tracks by length
+func TestSortTracksByDuration(t *testing.T) {
+	dvd := newFilterTestDVD()
+	original := trackIndexes(dvd.trackPointers())
+
+	ascending := dvd.SortTracksByDuration(true)
+	if ascending[0].Length != 30.0 || ascending[len(ascending)-1].Length != 9800.0 {
+		t.Errorf("Expected ascending sort from 30 to 9800 seconds, got %.0f to %.0f",
+			ascending[0].Length, ascending[len(ascending)-1].Length)
+	}
+	// Tracks 1 and 3 are both 2500 seconds long
+	if got := trackIndexes(ascending); !equalInts(got, []int{5, 2, 1, 3, 4}) {
+		t.Errorf("Expected ascending tracks [5 2 1 3 4], got %v", got)
+	}
+
+	descending := dvd.SortTracksByDuration(false)
+	if got := trackIndexes(descending); !equalInts(got, []int{4, 1, 3, 2, 5}) {
+		t.Errorf("Expected descending tracks [4 1 3 2 5], got %v", got)
+	}
+
+	if got := trackIndexes(dvd.trackPointers()); !equalInts(got, original) {
+		t.Errorf("Tracks were reordered: expected %v, got %v", original, got)
+	}
+}
+
+// TestSortTracksByChapterCount tests sorting tracks by number of chapters
+func TestSortTracksByChapterCount(t *testing.T) {
+	dvd, err := ParseFile("../source/s1d1.xml")
+	if err != nil {
+		t.Fatalf("Failed to parse DVD metadata: %v", err)
+	}
+	original := trackIndexes(dvd.trackPointers())
+
+	// Tracks 1-4 have 5 chapters, track 5 has 17, tracks 6-8 have 2 and tracks 9-10 have 1
+	ascending := dvd.SortTracksByChapterCount(true)
+	if got := trackIndexes(ascending); !equalInts(got, []int{9, 10, 6, 7, 8, 1, 2, 3, 4, 5}) {
+		t.Errorf("Expected ascending tracks [9 10 6 7 8 1 2 3 4 5], got %v", got)
+	}
+
+	descending := dvd.SortTracksByChapterCount(false)
+	if got := trackIndexes(descending); !equalInts(got, []int{5, 1, 2, 3, 4, 6, 7, 8, 9, 10}) {
+		t.Errorf("Expected descending tracks [5 1 2 3 4 6 7 8 9 10], got %v", got)
+	}
+
+	if got := trackIndexes(dvd.trackPointers()); !equalInts(got, original) {
+		t.Errorf("Tracks were reordered: expected %v, got %v", original, got)
+	}
+}
+
+// TestGetTracksWithLanguage tests filtering tracks by audio language
+func TestGetTracksWithLanguage(t *testing.T) {
+	fixture, err := ParseFile("../source/s1d1.xml")
+	if err != nil {
+		t.Fatalf("Failed to parse DVD metadata: %v", err)
+	}
+
+	lower := trackIndexes(fixture.GetTracksWithLanguage("english"))
+	upper := trackIndexes(fixture.GetTracksWithLanguage("English"))
+	if len(upper) == 0 || !equalInts(lower, upper) {
+		t.Errorf("Expected the same non-empty tracks for english and English, got %v and %v", lower, upper)
+	}
+	if tracks := fixture.GetTracksWithLanguage("Klingon"); tracks == nil || len(tracks) != 0 {
+		t.Errorf("Expected empty slice for missing language, got %v", trackIndexes(tracks))
+	}
+
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, AudioStreams: []AudioStream{{Index: 1, Language: "English"}}},
+		{Index: 2, AudioStreams: []AudioStream{{Index: 1, Language: "English"}, {Index: 2, Language: "Francais"}}},
+		{Index: 3},
+	}}
+	if got := trackIndexes(dvd.GetTracksWithLanguage("francais")); !equalInts(got, []int{2}) {
+		t.Errorf("Expected tracks [2] for language on the second stream, got %v", got)
+	}
+}
+
+// TestGetTracksWithSubtitleLanguage tests filtering tracks by subtitle language
+func TestGetTracksWithSubtitleLanguage(t *testing.T) {
+	fixture, err := ParseFile("../source/s1d1.xml")
+	if err != nil {
+		t.Fatalf("Failed to parse DVD metadata: %v", err)
+	}
+
+	// Only the episodes and play-all track have subtitles
+	if got := trackIndexes(fixture.GetTracksWithSubtitleLanguage("nederlands")); !equalInts(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected tracks [1 2 3 4 5], got %v", got)
+	}
+
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, AudioStreams: []AudioStream{{Index: 1, Language: "Deutsch"}}},
+		{Index: 2, SubtitleStreams: []SubtitleStream{{Index: 1, Language: "English"}, {Index: 2, Language: "Deutsch"}}},
+	}}
+	if got := trackIndexes(dvd.GetTracksWithSubtitleLanguage("Deutsch")); !equalInts(got, []int{2}) {
+		t.Errorf("Expected tracks [2] for subtitle language, got %v", got)
+	}
+	if got := trackIndexes(dvd.GetTracksWithLanguage("Deutsch")); !equalInts(got, []int{1}) {
+		t.Errorf("Expected tracks [1] for audio language, got %v", got)
+	}
+}
+
+// TestGetTracksWithBoth tests requiring an audio and a subtitle language
+func TestGetTracksWithBoth(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{
+			Index:           1,
+			AudioStreams:    []AudioStream{{Index: 1, Language: "Japanese"}},
+			SubtitleStreams: []SubtitleStream{{Index: 1, Language: "English"}},
+		},
+		{
+			Index:        2,
+			AudioStreams: []AudioStream{{Index: 1, Language: "Japanese"}},
+		},
+		{
+			Index:           3,
+			AudioStreams:    []AudioStream{{Index: 1, Language: "English"}},
+			SubtitleStreams: []SubtitleStream{{Index: 1, Language: "Japanese"}},
+		},
+		{
+			Index:           4,
+			AudioStreams:    []AudioStream{{Index: 1, Language: "English"}, {Index: 2, Language: "Japanese"}},
+			SubtitleStreams: []SubtitleStream{{Index: 1, Language: "Francais"}, {Index: 2, Language: "English"}},
+		},
+	}}
+
+	if got := trackIndexes(dvd.GetTracksWithBoth("japanese", "ENGLISH")); !equalInts(got, []int{1, 4}) {
+		t.Errorf("Expected tracks [1 4], got %v", got)
+	}
+	if got := trackIndexes(dvd.GetTracksWithBoth("Japanese", "Deutsch")); !equalInts(got, []int{}) {
+		t.Errorf("Expected no tracks, got %v", got)
+	}
+}
+
+// TestGetTracksWithNAudioStreams tests filtering by audio stream count
+func TestGetTracksWithNAudioStreams(t *testing.T) {
+	fixture, err := ParseFile("../source/s1d1.xml")
+	if err != nil {
+		t.Fatalf("Failed to parse DVD metadata: %v", err)
+	}
+
+	// The episodes and play-all track have two audio streams, the extras have one
+	if got := trackIndexes(fixture.GetTracksWithNAudioStreams(2)); !equalInts(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected tracks [1 2 3 4 5], got %v", got)
+	}
+	if got := trackIndexes(fixture.GetTracksWithNAudioStreams(3)); !equalInts(got, []int{}) {
+		t.Errorf("Expected no tracks with 3 audio streams, got %v", got)
+	}
+
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, AudioStreams: []AudioStream{{Index: 1}}},
+		{Index: 2},
+	}}
+	if got := trackIndexes(dvd.GetTracksWithNAudioStreams(0)); !equalInts(got, []int{2}) {
+		t.Errorf("Expected audioless tracks [2], got %v", got)
+	}
+	if got := trackIndexes(dvd.GetTracksWithNAudioStreams(-1)); !equalInts(got, []int{}) {
+		t.Errorf("Expected no tracks for negative count, got %v", got)
+	}
+}
+
+// TestGetTracksWithMinChapters tests filtering by minimum chapter count
+func TestGetTracksWithMinChapters(t *testing.T) {
+	fixture, err := ParseFile("../source/s1d1.xml")
+	if err != nil {
+		t.Fatalf("Failed to parse DVD metadata: %v", err)
+	}
+
+	// Episodes 1-4 have 5 chapters and the play-all track 5 has 17
+	if got := trackIndexes(fixture.GetTracksWithMinChapters(5)); !equalInts(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected tracks [1 2 3 4 5] for n=5, got %v", got)
+	}
+	if got := trackIndexes(fixture.GetTracksWithMinChapters(6)); !equalInts(got, []int{5}) {
+		t.Errorf("Expected tracks [5] for n=6, got %v", got)
+	}
+	for _, n := range []int{0, -1} {
+		if tracks := fixture.GetTracksWithMinChapters(n); len(tracks) != len(fixture.Tracks) {
+			t.Errorf("Expected all %d tracks for n=%d, got %d", len(fixture.Tracks), n, len(tracks))
+		}
+	}
+}
+
+// TestGetTracksWithoutChapters tests finding tracks with no chapters
+func TestGetTracksWithoutChapters(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Chapters: []Chapter{{Index: 1}, {Index: 2}}},
+		{Index: 2},
+		{Index: 3, Chapters: []Chapter{{Index: 1}}},
+	}}
+
+	if got := trackIndexes(dvd.GetTracksWithoutChapters()); !equalInts(got, []int{2}) {
+		t.Errorf("Expected tracks [2], got %v", got)
+	}
+}
+
+// TestGetTracksWithMultiAngle tests finding tracks with several camera angles
+func TestGetTracksWithMultiAngle(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Angles: 1},
+		{Index: 2, Angles: 3},
+		{Index: 3, Angles: 0},
+	}}
+
+	if got := trackIndexes(dvd.GetTracksWithMultiAngle()); !equalInts(got, []int{2}) {
+		t.Errorf("Expected tracks [2], got %v", got)
+	}
+
+	for _, track := range dvd.Tracks {
+		expected := track.Angles == 3
+		if got := track.IsMultiAngle(); got != expected {
+			t.Errorf("IsMultiAngle() with %d angles = %v, expected %v", track.Angles, got, expected)
+		}
+	}
+}
+
+// TestMinLength tests the minimum length predicate
+func TestMinLength(t *testing.T) {
+	dvd := newFilterTestDVD()
+
+	if got := trackIndexes(dvd.FilterTracksFunc(MinLength(2500))); !equalInts(got, []int{1, 3, 4}) {
+		t.Errorf("Expected tracks [1 3 4], got %v", got)
+	}
+	if got := trackIndexes(dvd.FilterTracksFunc(MinLength(0))); !equalInts(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected all tracks, got %v", got)
+	}
+}
+
+// TestGetTracksWithSurroundSound tests finding tracks with multichannel audio
+func TestGetTracksWithSurroundSound(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, AudioStreams: []AudioStream{{Format: "ac3", Channels: 2}}},
+		{Index: 2, AudioStreams: []AudioStream{{Format: "ac3", Channels: 2}, {Format: "dts", Channels: 6}}},
+		{Index: 3, AudioStreams: []AudioStream{{Format: "ac3", Channels: 0}}},
+		{Index: 4},
+	}}
+
+	if got := trackIndexes(dvd.GetTracksWithSurroundSound()); !equalInts(got, []int{2}) {
+		t.Errorf("Expected tracks [2], got %v", got)
+	}
+}
+
+// TestGetTracksWithCodec tests Dolby Digital and DTS filtering
+func TestGetTracksWithCodec(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, AudioStreams: []AudioStream{{Format: "ac3"}}},
+		{Index: 2, AudioStreams: []AudioStream{{Format: "DTS"}}},
+		{Index: 3, AudioStreams: []AudioStream{{Format: "AC3"}, {Format: "dts"}}},
+		{Index: 4, AudioStreams: []AudioStream{{Format: "lpcm"}}},
+	}}
+
+	if got := trackIndexes(dvd.GetTracksWithDolbyDigital()); !equalInts(got, []int{1, 3}) {
+		t.Errorf("Expected Dolby Digital tracks [1 3], got %v", got)
+	}
+	if got := trackIndexes(dvd.GetTracksWithDTS()); !equalInts(got, []int{2, 3}) {
+		t.Errorf("Expected DTS tracks [2 3], got %v", got)
+	}
+
+	testFile := "../source/s1d1.xml"
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		t.Skipf("Test file %s not found, skipping fixture check", testFile)
+	}
+	fixture, err := ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", testFile, err)
+	}
+	// Every track on s1d1.xml has ac3 audio and none has DTS
+	if tracks := fixture.GetTracksWithDolbyDigital(); len(tracks) != 10 {
+		t.Errorf("Expected 10 Dolby Digital tracks in %s, got %d", testFile, len(tracks))
+	}
+	if tracks := fixture.GetTracksWithDTS(); len(tracks) != 0 {
+		t.Errorf("Expected no DTS tracks in %s, got %d", testFile, len(tracks))
+	}
+}
+
+// TestGetTracksWithAudioFormat tests filtering by any audio format
+func TestGetTracksWithAudioFormat(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, AudioStreams: []AudioStream{{Format: "ac3"}}},
+		{Index: 2, AudioStreams: []AudioStream{{Format: "mp2"}, {Format: "ac3"}}},
+		{Index: 3, AudioStreams: []AudioStream{{Format: "lpcm"}}},
+		{Index: 4},
+	}}
+
+	testCases := []struct {
+		format   string
+		expected []int
+	}{
+		{"MP2", []int{2}},
+		{"LPCM", []int{3}},
+		{"ac3", []int{1, 2}},
+		{"flac", []int{}},
+	}
+
+	for _, tc := range testCases {
+		tracks := dvd.GetTracksWithAudioFormat(tc.format)
+		if tracks == nil {
+			t.Errorf("GetTracksWithAudioFormat(%q) returned nil, expected empty slice", tc.format)
+		}
+		if got := trackIndexes(tracks); !equalInts(got, tc.expected) {
+			t.Errorf("GetTracksWithAudioFormat(%q) = %v, expected %v", tc.format, got, tc.expected)
+		}
+	}
+}
+
+// TestSortTracksByIndex tests reordering out-of-order tracks in place
+func TestSortTracksByIndex(t *testing.T) {
+	dvd := &DVD{
+		LongestTrack: 2,
+		Tracks: []Track{
+			{Index: 3, Length: 100},
+			{Index: 1, Length: 200},
+			{Index: 2, Length: 300},
+		},
+	}
+
+	dvd.SortTracksByIndex()
+
+	got := make([]int, len(dvd.Tracks))
+	for i, track := range dvd.Tracks {
+		got[i] = track.Index
+	}
+	if !equalInts(got, []int{1, 2, 3}) {
+		t.Errorf("Expected tracks [1 2 3], got %v", got)
+	}
+	if dvd.LongestTrack != 2 {
+		t.Errorf("Expected LongestTrack to stay 2, got %d", dvd.LongestTrack)
+	}
+	if longest := dvd.GetLongestTrack(); longest == nil || longest.Index != 2 || longest.Length != 300 {
+		t.Errorf("Expected GetLongestTrack to return track 2, got %+v", longest)
+	}
+}
+
+// TestTracksByVTS tests grouping tracks by title set
+func TestTracksByVTS(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, VTS: 1},
+		{Index: 2, VTS: 2},
+		{Index: 3, VTS: 1},
+		{Index: 4, VTS: 2},
+		{Index: 5, VTS: 1},
+	}}
+
+	groups := dvd.TracksByVTS()
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 title sets, got %d", len(groups))
+	}
+
+	expected := map[int][]int{1: {1, 3, 5}, 2: {2, 4}}
+	for vts, indexes := range expected {
+		var got []int
+		for _, track := range groups[vts] {
+			got = append(got, track.Index)
+		}
+		if !equalInts(got, indexes) {
+			t.Errorf("VTS %d: expected tracks %v, got %v", vts, indexes, got)
+		}
+	}
+
+	if groups := (&DVD{}).TracksByVTS(); len(groups) != 0 {
+		t.Errorf("Expected no groups for an empty DVD, got %v", groups)
+	}
+}
@@ -0,0 +1,98 @@
+package dvd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GenerateMkvmergeCommand returns an mkvmerge command that remuxes input, a
+// rip of the track with all streams mapped, to output with a language and
+// default flag set on every audio and subtitle stream. Track IDs follow the
+// rip's stream order: video first, then audio and subtitle streams, each in
+// order of their position on the disc (Index), not their stream ID, which
+// depends on the codec. Streams repeating an earlier stream ID are skipped.
+// The primary audio stream is marked as the default track and subtitles are not.
+func GenerateMkvmergeCommand(t *Track, input, output string) string {
+	args := []string{"mkvmerge", "-o", fmt.Sprintf("%q", output)}
+
+	primary := t.PrimaryAudio()
+	tid := 1
+	for _, audio := range ripAudioStreams(t.AudioStreams) {
+		args = append(args, fmt.Sprintf("--language %d:%s", tid, mkvLanguage(audio.LanguageCode)))
+		flag := "no"
+		if primary != nil && audio.Index == primary.Index {
+			flag = "yes"
+		}
+		args = append(args, fmt.Sprintf("--default-track-flag %d:%s", tid, flag))
+		tid++
+	}
+	for _, sub := range ripSubtitleStreams(t.SubtitleStreams) {
+		args = append(args, fmt.Sprintf("--language %d:%s", tid, mkvLanguage(sub.LanguageCode)))
+		args = append(args, fmt.Sprintf("--default-track-flag %d:no", tid))
+		tid++
+	}
+
+	args = append(args, fmt.Sprintf("%q", input))
+	return strings.Join(args, " ")
+}
+
+// mkvLanguage returns the language code to pass to mkvmerge, using "und" for
+// streams whose language is missing or unknown ("xx" in lsdvd output)
+func mkvLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" || code == "xx" {
+		return "und"
+	}
+	return code
+}
+
+// ripAudioStreams returns a copy of streams in the order the ffmpeg dvdvideo
+// demuxer outputs them: by position (Index), skipping any stream whose stream
+// ID repeats that of an earlier one, as the demuxer does
+func ripAudioStreams(streams []AudioStream) []AudioStream {
+	sorted := append([]AudioStream(nil), streams...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Index < sorted[j].Index
+	})
+	seen := map[int]bool{}
+	ordered := []AudioStream{}
+	for _, audio := range sorted {
+		if !firstStreamID(seen, audio.StreamID) {
+			continue
+		}
+		ordered = append(ordered, audio)
+	}
+	return ordered
+}
+
+// ripSubtitleStreams returns a copy of streams in rip order, like ripAudioStreams
+func ripSubtitleStreams(streams []SubtitleStream) []SubtitleStream {
+	sorted := append([]SubtitleStream(nil), streams...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Index < sorted[j].Index
+	})
+	seen := map[int]bool{}
+	ordered := []SubtitleStream{}
+	for _, sub := range sorted {
+		if !firstStreamID(seen, sub.StreamID) {
+			continue
+		}
+		ordered = append(ordered, sub)
+	}
+	return ordered
+}
+
+// firstStreamID records id in seen and reports whether it had not been seen
+// before. Unparseable IDs are always reported as new.
+func firstStreamID(seen map[int]bool, id string) bool {
+	n, err := parseStreamID(id)
+	if err != nil {
+		return true
+	}
+	if seen[n] {
+		return false
+	}
+	seen[n] = true
+	return true
+}
@@ -0,0 +1,72 @@
+package dvd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateMkvmergeCommand tests mkvmerge language and default flags
+func TestGenerateMkvmergeCommand(t *testing.T) {
+	track := &Track{
+		Index: 1,
+		AudioStreams: []AudioStream{
+			{Index: 1, LanguageCode: "en", StreamID: "0x80", APMode: apModeKaraoke},
+			{Index: 2, LanguageCode: "fr", StreamID: "0x81"},
+		},
+		SubtitleStreams: []SubtitleStream{
+			{Index: 1, LanguageCode: "nl", StreamID: "0x21"},
+			{Index: 2, LanguageCode: "", StreamID: "0x20"},
+		},
+	}
+
+	cmd := GenerateMkvmergeCommand(track, "rip.mkv", "out.mkv")
+
+	streams := len(track.AudioStreams) + len(track.SubtitleStreams)
+	if got := strings.Count(cmd, "--language "); got != streams {
+		t.Errorf("Expected %d --language flags, got %d: %s", streams, got, cmd)
+	}
+
+	expected := []string{
+		`mkvmerge -o "out.mkv" `,
+		"--language 1:en --default-track-flag 1:no",
+		"--language 2:fr --default-track-flag 2:yes",
+		"--language 3:nl --default-track-flag 3:no",
+		"--language 4:und --default-track-flag 4:no",
+		` "rip.mkv"`,
+	}
+	for _, part := range expected {
+		if !strings.Contains(cmd, part) {
+			t.Errorf("Expected command to contain %q: %s", part, cmd)
+		}
+	}
+
+	// Stream IDs depend on the codec, so a dts stream before an ac3 stream
+	// has the higher ID but still comes first in the rip
+	mixed := &Track{
+		Index: 2,
+		AudioStreams: []AudioStream{
+			{Index: 2, LanguageCode: "fr", Format: "ac3", StreamID: "0x81"},
+			{Index: 1, LanguageCode: "en", Format: "dts", StreamID: "0x88"},
+		},
+		SubtitleStreams: []SubtitleStream{
+			{Index: 1, LanguageCode: "en", StreamID: "0x20"},
+			{Index: 2, LanguageCode: "en", StreamID: "0x20"},
+			{Index: 3, LanguageCode: "fr", StreamID: "0x21"},
+		},
+	}
+	cmd = GenerateMkvmergeCommand(mixed, "rip.mkv", "out.mkv")
+	expected = []string{
+		"--language 1:en --default-track-flag 1:yes",
+		"--language 2:fr --default-track-flag 2:no",
+		"--language 3:en --default-track-flag 3:no",
+		"--language 4:fr --default-track-flag 4:no",
+	}
+	for _, part := range expected {
+		if !strings.Contains(cmd, part) {
+			t.Errorf("Expected command to contain %q: %s", part, cmd)
+		}
+	}
+	if strings.Contains(cmd, "5:") {
+		t.Errorf("Expected the repeated subtitle stream ID to be skipped: %s", cmd)
+	}
+}
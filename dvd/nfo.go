@@ -0,0 +1,95 @@
+package dvd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// movieNFO is the root element of a Kodi/Plex movie NFO file
+type movieNFO struct {
+	XMLName  xml.Name    `xml:"movie"`
+	Title    string      `xml:"title,omitempty"`
+	Runtime  int         `xml:"runtime"`
+	FileInfo nfoFileInfo `xml:"fileinfo"`
+}
+
+// nfoFileInfo holds the stream details of a movie NFO
+type nfoFileInfo struct {
+	Video     nfoVideo      `xml:"streamdetails>video"`
+	Audio     []nfoAudio    `xml:"streamdetails>audio"`
+	Subtitles []nfoSubtitle `xml:"streamdetails>subtitle"`
+}
+
+// nfoVideo describes the video stream in a movie NFO
+type nfoVideo struct {
+	Width             int    `xml:"width"`
+	Height            int    `xml:"height"`
+	Aspect            string `xml:"aspect,omitempty"`
+	DurationInSeconds int    `xml:"durationinseconds"`
+}
+
+// nfoAudio describes an audio stream in a movie NFO
+type nfoAudio struct {
+	Codec    string `xml:"codec,omitempty"`
+	Language string `xml:"language,omitempty"`
+	Channels int    `xml:"channels,omitempty"`
+}
+
+// nfoSubtitle describes a subtitle stream in a movie NFO
+type nfoSubtitle struct {
+	Language string `xml:"language,omitempty"`
+}
+
+// ToMovieNFO generates a minimal Kodi/Plex compatible movie NFO for the main
+// feature of the DVD. The runtime and stream details are taken from the longest
+// track. A title of "unknown" is omitted.
+func (d *DVD) ToMovieNFO() ([]byte, error) {
+	track := d.GetLongestTrack()
+	if track == nil {
+		return nil, fmt.Errorf("no longest track to describe")
+	}
+
+	nfo := movieNFO{
+		Runtime: int(math.Round(track.Length / 60)),
+		FileInfo: nfoFileInfo{
+			Video: nfoVideo{
+				Width:             track.Width,
+				Height:            track.Height,
+				Aspect:            track.Aspect,
+				DurationInSeconds: int(math.Round(track.Length)),
+			},
+		},
+	}
+	if d.HasTitle() {
+		nfo.Title = strings.TrimSpace(d.Title)
+	}
+
+	for _, audio := range track.AudioStreams {
+		nfo.FileInfo.Audio = append(nfo.FileInfo.Audio, nfoAudio{
+			Codec:    audio.Format,
+			Language: nfoLanguage(audio.LanguageCode, audio.Language),
+			Channels: audio.Channels,
+		})
+	}
+	for _, sub := range track.SubtitleStreams {
+		nfo.FileInfo.Subtitles = append(nfo.FileInfo.Subtitles, nfoSubtitle{
+			Language: nfoLanguage(sub.LanguageCode, sub.Language),
+		})
+	}
+
+	data, err := xml.MarshalIndent(nfo, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate NFO: %v", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// nfoLanguage returns the language code if present, falling back to the language name
+func nfoLanguage(code, name string) string {
+	if code != "" {
+		return code
+	}
+	return name
+}
@@ -0,0 +1,70 @@
+package dvd
+
+import (
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestToMovieNFO tests NFO generation for the main feature
+func TestToMovieNFO(t *testing.T) {
+	dvd := &DVD{
+		Title:        "unknown",
+		LongestTrack: 2,
+		Tracks: []Track{
+			{Index: 1, Length: 120.0},
+			{
+				Index:  2,
+				Length: 5843.4,
+				Width:  720,
+				Height: 576,
+				Aspect: "16/9",
+				AudioStreams: []AudioStream{
+					{Index: 1, LanguageCode: "en", Language: "English", Format: "ac3", Channels: 6},
+					{Index: 2, LanguageCode: "fr", Language: "Francais", Format: "ac3", Channels: 2},
+				},
+				SubtitleStreams: []SubtitleStream{
+					{Index: 1, LanguageCode: "nl", Language: "Nederlands"},
+				},
+			},
+		},
+	}
+
+	data, err := dvd.ToMovieNFO()
+	if err != nil {
+		t.Fatalf("ToMovieNFO failed: %v", err)
+	}
+
+	if strings.Contains(string(data), "<title>") {
+		t.Errorf("Expected unknown title to be omitted, got:\n%s", data)
+	}
+
+	var nfo movieNFO
+	if err := xml.Unmarshal(data, &nfo); err != nil {
+		t.Fatalf("Generated NFO is not valid XML: %v", err)
+	}
+
+	expectedRuntime := int(math.Round(dvd.GetLongestTrack().Length / 60))
+	if nfo.Runtime != expectedRuntime {
+		t.Errorf("Expected runtime %d, got %d", expectedRuntime, nfo.Runtime)
+	}
+	if len(nfo.FileInfo.Audio) != 2 {
+		t.Fatalf("Expected 2 audio streams, got %d", len(nfo.FileInfo.Audio))
+	}
+	if nfo.FileInfo.Audio[0].Language != "en" || nfo.FileInfo.Audio[0].Channels != 6 {
+		t.Errorf("Unexpected first audio stream: %+v", nfo.FileInfo.Audio[0])
+	}
+	if len(nfo.FileInfo.Subtitles) != 1 || nfo.FileInfo.Subtitles[0].Language != "nl" {
+		t.Errorf("Unexpected subtitle streams: %+v", nfo.FileInfo.Subtitles)
+	}
+
+	dvd.Title = "Law And Order"
+	data, err = dvd.ToMovieNFO()
+	if err != nil {
+		t.Fatalf("ToMovieNFO failed: %v", err)
+	}
+	if !strings.Contains(string(data), "<title>Law And Order</title>") {
+		t.Errorf("Expected title element, got:\n%s", data)
+	}
+}
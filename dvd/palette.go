@@ -0,0 +1,66 @@
+package dvd
+
+import (
+	"encoding/xml"
+	"math"
+	"strconv"
+)
+
+// MarshalXML implements xml.Marshaler. A palette without colors is omitted
+// entirely rather than written as an empty <palette> element, as lsdvd does
+// for tracks without one.
+func (p Palette) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(p.Colors) == 0 {
+		return nil
+	}
+	type palette Palette // avoids recursing into MarshalXML
+	return e.EncodeElement(palette(p), start)
+}
+
+// RGB converts the palette colors to RGB triplets.
+//
+// lsdvd prints each palette entry as a 6-digit hex value holding the Y, Cr
+// and Cb components (the byte order used in the DVD IFO files). The values are
+// converted using the ITU-R BT.601 studio-range equations. Malformed entries
+// are skipped.
+func (p Palette) RGB() [][3]uint8 {
+	colors := make([][3]uint8, 0, len(p.Colors))
+	for _, c := range p.Colors {
+		if len(c) != 6 {
+			continue
+		}
+		v, err := strconv.ParseUint(c, 16, 32)
+		if err != nil {
+			continue
+		}
+		y := float64(v >> 16 & 0xff)
+		cr := float64(v >> 8 & 0xff)
+		cb := float64(v & 0xff)
+		colors = append(colors, ycrcbToRGB(y, cr, cb))
+	}
+	return colors
+}
+
+// ycrcbToRGB converts a studio-range BT.601 YCrCb color to RGB
+func ycrcbToRGB(y, cr, cb float64) [3]uint8 {
+	y = 1.164 * (y - 16)
+	cr -= 128
+	cb -= 128
+	return [3]uint8{
+		clampUint8(y + 1.596*cr),
+		clampUint8(y - 0.813*cr - 0.391*cb),
+		clampUint8(y + 2.018*cb),
+	}
+}
+
+// clampUint8 rounds v and clamps it to the 0-255 range
+func clampUint8(v float64) uint8 {
+	v = math.Round(v)
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
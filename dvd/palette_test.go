@@ -0,0 +1,85 @@
+package dvd
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// TestPaletteRGB tests conversion of YCrCb palette entries to RGB
+func TestPaletteRGB(t *testing.T) {
+	palette := Palette{Colors: []string{
+		"108080", // black
+		"eb8080", // white
+		"not-hex",
+		"51f05a", // red
+		"1234",
+		"296ef0", // blue
+	}}
+
+	expected := [][3]uint8{
+		{0, 0, 0},
+		{255, 255, 255},
+		{255, 0, 0},
+		{0, 0, 255},
+	}
+
+	rgb := palette.RGB()
+	if len(rgb) != len(expected) {
+		t.Fatalf("Expected %d colors, got %d", len(expected), len(rgb))
+	}
+
+	for i, want := range expected {
+		got := rgb[i]
+		for c := 0; c < 3; c++ {
+			diff := int(got[c]) - int(want[c])
+			if diff < -1 || diff > 1 {
+				t.Errorf("Color %d: expected %v, got %v", i, want, got)
+				break
+			}
+		}
+	}
+}
+
+// TestPaletteMarshalXML tests that empty palettes are omitted and survive a round trip
+func TestPaletteMarshalXML(t *testing.T) {
+	original := &DVD{
+		Device: "./test",
+		Tracks: []Track{
+			{Index: 1, Length: 100},
+			{Index: 2, Length: 200, Palette: Palette{Colors: []string{"108080", "eb8080"}}},
+		},
+	}
+
+	data, err := xml.Marshal(original)
+	if err != nil {
+		t.Fatalf("Failed to marshal DVD: %v", err)
+	}
+	if got := strings.Count(string(data), "<palette>"); got != 1 {
+		t.Errorf("Expected 1 palette element, got %d: %s", got, data)
+	}
+
+	parsed, err := ParseBytes(data)
+	if err != nil {
+		t.Fatalf("Failed to parse marshalled DVD: %v", err)
+	}
+	if len(parsed.Tracks) != 2 {
+		t.Fatalf("Expected 2 tracks, got %d", len(parsed.Tracks))
+	}
+	if len(parsed.Tracks[0].Palette.Colors) != 0 {
+		t.Errorf("Expected no palette colors on track 1, got %v", parsed.Tracks[0].Palette.Colors)
+	}
+	colors := parsed.Tracks[1].Palette.Colors
+	if len(colors) != 2 || colors[0] != "108080" || colors[1] != "eb8080" {
+		t.Errorf("Expected palette colors to round-trip on track 2, got %v", colors)
+	}
+
+	// A track with no palette produces no palette element at all
+	data, err = xml.Marshal(Track{Index: 1})
+	if err != nil {
+		t.Fatalf("Failed to marshal track: %v", err)
+	}
+	if strings.Contains(string(data), "palette") {
+		t.Errorf("Expected no palette element, got %s", data)
+	}
+}
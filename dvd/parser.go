@@ -4,13 +4,20 @@ package dvd
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // DVD represents the complete DVD metadata structure
 type DVD struct {
-	XMLName      xml.Name `xml:"lsdvd"`
+	XMLName      xml.Name `xml:"lsdvd" json:"-"`
 	Device       string   `xml:"device"`
 	Title        string   `xml:"title"`
 	VMGID        string   `xml:"vmg_id"`
@@ -77,45 +84,290 @@ type Chapter struct {
 
 // Cell represents a cell within a track
 type Cell struct {
-	Index  int     `xml:"ix"`
-	Length float64 `xml:"length"`
+	Index       int     `xml:"ix"`
+	Length      float64 `xml:"length"`
+	FirstSector int64   `xml:"first_sector"` // only present in newer lsdvd output
+	LastSector  int64   `xml:"last_sector"`  // only present in newer lsdvd output
 }
 
-// ParseFile parses a single XML file and returns DVD metadata
+// ErrEmptyDocument is returned when the XML data is empty or has no root element,
+// as happens with zero-byte or partially written files
+var ErrEmptyDocument = errors.New("empty XML document")
+
+// ParseError describes a failure to parse an lsdvd document. File is set when
+// the document was read by ParseFile, and Document is the 1-based position of
+// the failing document when several were decoded from one stream. Line and
+// Offset locate the error when it is an XML syntax error: Line is the line
+// number, and Offset the approximate byte offset, counted after malformed
+// entities have been repaired.
+type ParseError struct {
+	File     string
+	Document int
+	Line     int
+	Offset   int64
+	Err      error
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	switch {
+	case e.File != "" && e.Document > 0:
+		return fmt.Sprintf("failed to parse file %s, document %d: %v", e.File, e.Document, e.Err)
+	case e.File != "":
+		return fmt.Sprintf("failed to parse file %s: %v", e.File, e.Err)
+	case e.Document > 0:
+		return fmt.Sprintf("failed to parse XML document %d: %v", e.Document, e.Err)
+	}
+	return fmt.Sprintf("failed to parse XML: %v", e.Err)
+}
+
+// newParseError wraps a decoding error in a *ParseError positioned at the
+// decoder's current offset and, for syntax errors, line
+func newParseError(decoder *xml.Decoder, err error) *ParseError {
+	parseErr := &ParseError{Offset: decoder.InputOffset(), Err: err}
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		parseErr.Line = syntaxErr.Line
+	}
+	return parseErr
+}
+
+// Unwrap returns the underlying error, so errors.Is(err, ErrEmptyDocument) and
+// errors.As with *xml.SyntaxError work on a *ParseError
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseFile parses a single XML file and returns DVD metadata. Parse failures
+// are returned as a *ParseError naming the file.
 func ParseFile(filename string) (*DVD, error) {
 	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %v", filename, err)
 	}
 
-	return ParseBytes(data)
+	dvd, err := ParseBytes(data)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			parseErr.File = filename
+			return nil, parseErr
+		}
+		return nil, &ParseError{File: filename, Err: err}
+	}
+	return dvd, nil
 }
 
 // ParseBytes parses DVD metadata from XML byte data
 func ParseBytes(data []byte) (*DVD, error) {
-	// Fix common XML entity issues in the data
-	// Fix malformed entity &Scan -> &amp;Scan
-	data = bytes.ReplaceAll(data, []byte("Pan&Scan"), []byte("Pan&amp;Scan"))
-	// Fix other potential malformed entities
-	data = bytes.ReplaceAll(data, []byte("&Letterbox"), []byte("&amp;Letterbox"))
+	return unmarshalDVD(fixEntities(data))
+}
+
+// ParseBytesVerbose parses DVD metadata like ParseBytes, additionally returning
+// a warning for each repair applied to the data and each lsdvd placeholder
+// found, such as an escaped stray ampersand or the "unknown" title. The
+// returned DVD is the same as ParseBytes would return.
+func ParseBytesVerbose(data []byte) (*DVD, []string, error) {
+	data, warnings := repairEntities(data)
+
+	dvd, err := unmarshalDVD(data)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	if strings.EqualFold(strings.TrimSpace(dvd.Title), unknownTitle) {
+		warnings = append(warnings, fmt.Sprintf("title is the lsdvd %q placeholder", unknownTitle))
+	}
+	return dvd, warnings, nil
+}
+
+// unmarshalDVD parses a single lsdvd document whose entities have been fixed
+func unmarshalDVD(data []byte) (*DVD, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
 
 	var dvd DVD
-	err := xml.Unmarshal(data, &dvd)
+	err := decoder.Decode(&dvd)
+	if err == io.EOF {
+		return nil, ErrEmptyDocument
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse XML: %v", err)
+		return nil, newParseError(decoder, err)
 	}
 
 	return &dvd, nil
 }
 
-// GetLongestTrack returns the longest track from the DVD, or nil if not found
+// ParseMultiple parses a stream of concatenated lsdvd documents, such as the
+// output of several lsdvd runs appended to one file, returning one DVD per
+// document. Parse failures are returned as a *ParseError giving the document.
+func ParseMultiple(data []byte) ([]*DVD, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(fixEntities(data)))
+
+	var dvds []*DVD
+	for {
+		var dvd DVD
+		err := decoder.Decode(&dvd)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			parseErr := newParseError(decoder, err)
+			parseErr.Document = len(dvds) + 1
+			return nil, parseErr
+		}
+		dvds = append(dvds, &dvd)
+	}
+
+	if len(dvds) == 0 {
+		return nil, ErrEmptyDocument
+	}
+	return dvds, nil
+}
+
+// entityFixes lists the malformed entities found in lsdvd output and their repairs
+var entityFixes = []struct {
+	from, to string
+}{
+	// Fix malformed entity &Scan -> &amp;Scan
+	{"Pan&Scan", "Pan&amp;Scan"},
+	// Fix other potential malformed entities
+	{"&Letterbox", "&amp;Letterbox"},
+}
+
+// decimalComma matches a length or frame rate written with a decimal comma,
+// as lsdvd does when run in some locales (e.g. <length>100,5</length>)
+var decimalComma = regexp.MustCompile(`<(length|fps)>(\s*-?\d+),(\d+\s*)</`)
+
+// fixEntities fixes common XML entity and number format issues in lsdvd output
+func fixEntities(data []byte) []byte {
+	data, _ = repairEntities(data)
+	return data
+}
+
+// repairEntities fixes common XML entity and number format issues in lsdvd
+// output, returning a warning naming the enclosing element for every repair made
+func repairEntities(data []byte) ([]byte, []string) {
+	var warnings []string
+	for _, fix := range entityFixes {
+		from := []byte(fix.from)
+		for offset := 0; ; {
+			i := bytes.Index(data[offset:], from)
+			if i < 0 {
+				break
+			}
+			offset += i
+			warnings = append(warnings, fmt.Sprintf("escaped stray ampersand in <%s> (%q)",
+				enclosingElement(data, offset), fix.from))
+			offset += len(from)
+		}
+		data = bytes.ReplaceAll(data, from, []byte(fix.to))
+	}
+
+	for _, m := range decimalComma.FindAllSubmatch(data, -1) {
+		warnings = append(warnings, fmt.Sprintf("replaced decimal comma in <%s> (%q)",
+			m[1], strings.TrimSpace(string(m[2])+","+string(m[3]))))
+	}
+	data = decimalComma.ReplaceAll(data, []byte("<$1>$2.$3</"))
+	return data, warnings
+}
+
+// enclosingElement returns the name of the last tag opened before pos in data
+func enclosingElement(data []byte, pos int) string {
+	start := bytes.LastIndexByte(data[:pos], '<')
+	if start < 0 {
+		return ""
+	}
+	tag := data[start+1 : pos]
+	if end := bytes.IndexAny(tag, " \t\r\n/>"); end >= 0 {
+		tag = tag[:end]
+	}
+	return string(tag)
+}
+
+// unknownTitle is the placeholder lsdvd writes when a disc has no title
+const unknownTitle = "unknown"
+
+// Normalize cleans up placeholder values written by lsdvd. The "unknown"
+// title sentinel is replaced with an empty string.
+func (d *DVD) Normalize() {
+	d.Title = strings.TrimSpace(d.Title)
+	if strings.EqualFold(d.Title, unknownTitle) {
+		d.Title = ""
+	}
+}
+
+// HasTitle reports whether the DVD has a real title, ignoring the "unknown" placeholder
+func (d *DVD) HasTitle() bool {
+	title := strings.TrimSpace(d.Title)
+	return title != "" && !strings.EqualFold(title, unknownTitle)
+}
+
+// DisplayTitle returns the DVD title, or when the disc has no real title, a
+// name derived from the last component of the device path, with each word
+// capitalized (for example "./s1d1/LAW_and_order" becomes "Law And Order")
+func (d *DVD) DisplayTitle() string {
+	if d.HasTitle() {
+		return strings.TrimSpace(d.Title)
+	}
+
+	name := path.Base(strings.ReplaceAll(strings.TrimSpace(d.Device), "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	})
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(r)) + strings.ToLower(word[size:])
+	}
+	return strings.Join(words, " ")
+}
+
+// GetLongestTrack returns the track lsdvd declared as longest, matched by
+// index. If no track has that index, the track at that 1-based slice position
+// is returned instead, or nil if there is none.
 func (d *DVD) GetLongestTrack() *Track {
+	if track := d.GetTrackByIndex(d.LongestTrack); track != nil {
+		return track
+	}
 	if d.LongestTrack > 0 && d.LongestTrack <= len(d.Tracks) {
 		return &d.Tracks[d.LongestTrack-1] // Convert to 0-based index
 	}
 	return nil
 }
 
+// LongestTrackConsistent reports whether the track declared as longest by
+// lsdvd exists and no other track is longer than it
+func (d *DVD) LongestTrackConsistent() bool {
+	declared := d.GetTrackByIndex(d.LongestTrack)
+	if declared == nil {
+		return false
+	}
+	for _, track := range d.Tracks {
+		if track.Length > declared.Length {
+			return false
+		}
+	}
+	return true
+}
+
+// GetShortestTrack returns the track with the smallest non-zero length, or nil
+// if the DVD has no tracks with a positive length
+func (d *DVD) GetShortestTrack() *Track {
+	var shortest *Track
+	for i := range d.Tracks {
+		track := &d.Tracks[i]
+		if track.Length <= 0 {
+			continue
+		}
+		if shortest == nil || track.Length < shortest.Length {
+			shortest = track
+		}
+	}
+	return shortest
+}
+
 // GetTrackByIndex returns a track by its index (1-based), or nil if not found
 func (d *DVD) GetTrackByIndex(index int) *Track {
 	for i := range d.Tracks {
@@ -126,6 +378,17 @@ func (d *DVD) GetTrackByIndex(index int) *Track {
 	return nil
 }
 
+// GetTrackByVTS returns the first track with the given title set number (VTS)
+// and title number within that set (TTN), or nil if not found
+func (d *DVD) GetTrackByVTS(vts, ttn int) *Track {
+	for i := range d.Tracks {
+		if d.Tracks[i].VTS == vts && d.Tracks[i].TTN == ttn {
+			return &d.Tracks[i]
+		}
+	}
+	return nil
+}
+
 // GetTotalDuration returns the total duration of all tracks in seconds
 func (d *DVD) GetTotalDuration() float64 {
 	var total float64
@@ -184,13 +447,25 @@ func (d *DVD) FindContentAroundDuration(targetMinutes, toleranceMinutes float64)
 	targetSeconds := targetMinutes * 60.0
 	toleranceSeconds := toleranceMinutes * 60.0
 
+	return d.findContentInSeconds(targetSeconds-toleranceSeconds, targetSeconds+toleranceSeconds)
+}
+
+// FindContentInRange finds tracks and chapters with a duration between
+// minMinutes and maxMinutes inclusive
+func (d *DVD) FindContentInRange(minMinutes, maxMinutes float64) []ContentMatch {
+	return d.findContentInSeconds(minMinutes*60.0, maxMinutes*60.0)
+}
+
+// findContentInSeconds finds tracks with a length between minSeconds and
+// maxSeconds inclusive, and chapters in that range within tracks that don't match
+func (d *DVD) findContentInSeconds(minSeconds, maxSeconds float64) []ContentMatch {
 	var matches []ContentMatch
 
 	for i := range d.Tracks {
 		track := &d.Tracks[i]
 
 		// Check if the entire track matches
-		if track.Length >= (targetSeconds-toleranceSeconds) && track.Length <= (targetSeconds+toleranceSeconds) {
+		if track.Length >= minSeconds && track.Length <= maxSeconds {
 			matches = append(matches, ContentMatch{
 				Type:     "track",
 				Track:    track,
@@ -203,7 +478,7 @@ func (d *DVD) FindContentAroundDuration(targetMinutes, toleranceMinutes float64)
 		// Check chapters within this track
 		for j := range track.Chapters {
 			chapter := &track.Chapters[j]
-			if chapter.Length >= (targetSeconds-toleranceSeconds) && chapter.Length <= (targetSeconds+toleranceSeconds) {
+			if chapter.Length >= minSeconds && chapter.Length <= maxSeconds {
 				matches = append(matches, ContentMatch{
 					Type:     "chapter",
 					Track:    track,
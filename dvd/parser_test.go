@@ -1,6 +1,12 @@
 package dvd
 
 import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -314,3 +320,488 @@ func TestFindFortyMinuteContent(t *testing.T) {
 		}
 	}
 }
+
+// TestGetShortestTrack tests finding the shortest track by length
+func TestGetShortestTrack(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Length: 2400.0},
+		{Index: 2, Length: 0},
+		{Index: 3, Length: 15.5},
+		{Index: 4, Length: 600.0},
+	}}
+
+	shortest := dvd.GetShortestTrack()
+	if shortest == nil {
+		t.Fatal("GetShortestTrack should return a track")
+	}
+	if shortest.Index != 3 {
+		t.Errorf("Expected shortest track index 3, got %d", shortest.Index)
+	}
+
+	zero := &DVD{Tracks: []Track{{Index: 1}, {Index: 2}}}
+	if track := zero.GetShortestTrack(); track != nil {
+		t.Errorf("Expected nil for all-zero lengths, got track %d", track.Index)
+	}
+
+	empty := &DVD{}
+	if track := empty.GetShortestTrack(); track != nil {
+		t.Errorf("Expected nil for DVD without tracks, got track %d", track.Index)
+	}
+}
+
+// TestNormalize tests replacing the "unknown" title placeholder
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		title    string
+		expected string
+		hasTitle bool
+	}{
+		{"unknown", "", false},
+		{"UNKNOWN", "", false},
+		{" unknown ", "", false},
+		{"", "", false},
+		{"LAW_AND_ORDER_SVU", "LAW_AND_ORDER_SVU", true},
+		{"Unknown Soldier", "Unknown Soldier", true},
+	}
+
+	for _, tc := range testCases {
+		dvd := &DVD{Title: tc.title}
+		if dvd.HasTitle() != tc.hasTitle {
+			t.Errorf("HasTitle() for %q = %v, expected %v", tc.title, !tc.hasTitle, tc.hasTitle)
+		}
+
+		dvd.Normalize()
+		if dvd.Title != tc.expected {
+			t.Errorf("Normalize() for %q gave title %q, expected %q", tc.title, dvd.Title, tc.expected)
+		}
+		if dvd.HasTitle() != tc.hasTitle {
+			t.Errorf("HasTitle() after Normalize for %q = %v, expected %v", tc.title, !tc.hasTitle, tc.hasTitle)
+		}
+	}
+}
+
+// TestDisplayTitle tests deriving a display title from the device path
+func TestDisplayTitle(t *testing.T) {
+	testCases := []struct {
+		title    string
+		device   string
+		expected string
+	}{
+		{"unknown", "./s1d1/Law And Order Svu", "Law And Order Svu"},
+		{"", "/media/dvd/law_and_order-svu", "Law And Order Svu"},
+		{"unknown", "./", ""},
+		{"", "", ""},
+		{"LAW_AND_ORDER_SVU", "./s1d1/Law And Order Svu", "LAW_AND_ORDER_SVU"},
+		{"unknown", "./LAW_AND_ORDER", "Law And Order"},
+		{"", "law_AND_order", "Law And Order"},
+	}
+
+	for _, tc := range testCases {
+		dvd := &DVD{Title: tc.title, Device: tc.device}
+		if got := dvd.DisplayTitle(); got != tc.expected {
+			t.Errorf("DisplayTitle() for title %q, device %q = %q, expected %q",
+				tc.title, tc.device, got, tc.expected)
+		}
+	}
+}
+
+// TestParseEmptyFile tests the error for zero-byte and header-only files
+func TestParseEmptyFile(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"empty.xml", ""},
+		{"header.xml", `<?xml version="1.0" encoding="UTF-8"?>` + "\n"},
+	}
+
+	for _, tc := range testCases {
+		filename := filepath.Join(dir, tc.name)
+		if err := os.WriteFile(filename, []byte(tc.content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", filename, err)
+		}
+
+		_, err := ParseFile(filename)
+		if err == nil {
+			t.Errorf("Expected error for %s, got nil", tc.name)
+			continue
+		}
+		if !errors.Is(err, ErrEmptyDocument) {
+			t.Errorf("Expected ErrEmptyDocument for %s, got %v", tc.name, err)
+		}
+		if !strings.Contains(err.Error(), filename) {
+			t.Errorf("Expected error to mention %s, got %q", filename, err.Error())
+		}
+	}
+
+	// Truncated documents still report the XML syntax error
+	if _, err := ParseBytes([]byte("<lsdvd><device>./test")); err == nil || errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("Expected syntax error for truncated XML, got %v", err)
+	}
+}
+
+// TestGetTrackByVTS tests looking up a track by title set and title number
+func TestGetTrackByVTS(t *testing.T) {
+	testFile := "../source/s1d1.xml"
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		t.Skipf("Test file %s not found, skipping test", testFile)
+	}
+
+	dvd, err := ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", testFile, err)
+	}
+
+	testCases := []struct {
+		vts, ttn int
+		expected int // expected track index, 0 for not found
+	}{
+		{1, 1, 1},
+		{1, 5, 5},
+		{2, 1, 6},
+		{3, 2, 8},
+		{3, 5, 0},
+		{9, 1, 0},
+	}
+
+	for _, tc := range testCases {
+		track := dvd.GetTrackByVTS(tc.vts, tc.ttn)
+		if tc.expected == 0 {
+			if track != nil {
+				t.Errorf("GetTrackByVTS(%d, %d) should return nil, got track %d", tc.vts, tc.ttn, track.Index)
+			}
+			continue
+		}
+		if track == nil {
+			t.Errorf("GetTrackByVTS(%d, %d) should return track %d, got nil", tc.vts, tc.ttn, tc.expected)
+		} else if track.Index != tc.expected {
+			t.Errorf("GetTrackByVTS(%d, %d) = track %d, expected %d", tc.vts, tc.ttn, track.Index, tc.expected)
+		}
+	}
+}
+
+// TestFindContentInRange tests content finding with explicit bounds
+func TestFindContentInRange(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{Index: 1, Length: 2500.0, Chapters: []Chapter{{Index: 1, Length: 2500.0}}},
+		{Index: 2, Length: 1300.0},
+		{Index: 3, Length: 9800.0, Chapters: []Chapter{
+			{Index: 1, Length: 2460.0},
+			{Index: 2, Length: 300.0},
+			{Index: 3, Length: 2520.0},
+		}},
+	}}
+
+	// A wide range catches tracks 1 and 2, and chapters of the long track
+	matches := dvd.FindContentInRange(20.0, 50.0)
+	var got []string
+	for _, match := range matches {
+		if match.Type == "track" {
+			got = append(got, fmt.Sprintf("t%d", match.Track.Index))
+		} else {
+			got = append(got, fmt.Sprintf("t%dc%d", match.Track.Index, match.Chapter.Index))
+		}
+	}
+	expected := "t1 t2 t3c1 t3c3"
+	if strings.Join(got, " ") != expected {
+		t.Errorf("Expected matches %q, got %q", expected, strings.Join(got, " "))
+	}
+
+	// A narrow range catches only a chapter
+	matches = dvd.FindContentInRange(4.0, 6.0)
+	if len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Type != "chapter" || matches[0].Track.Index != 3 || matches[0].Chapter.Index != 2 {
+		t.Errorf("Expected chapter 2 of track 3, got %s in track %d", matches[0].Type, matches[0].Track.Index)
+	}
+}
+
+// TestLongestTrackConsistent tests validating the declared longest track
+func TestLongestTrackConsistent(t *testing.T) {
+	dvd := &DVD{
+		LongestTrack: 2,
+		Tracks: []Track{
+			{Index: 1, Length: 2500.0},
+			{Index: 2, Length: 9857.0},
+			{Index: 3, Length: 2460.0},
+		},
+	}
+	if !dvd.LongestTrackConsistent() {
+		t.Error("Expected declared longest track 2 to be consistent")
+	}
+
+	dvd.LongestTrack = 1
+	if dvd.LongestTrackConsistent() {
+		t.Error("Expected declared longest track 1 to be inconsistent")
+	}
+
+	dvd.LongestTrack = 99
+	if dvd.LongestTrackConsistent() {
+		t.Error("Expected missing declared longest track to be inconsistent")
+	}
+}
+
+// TestParseMultiple tests parsing concatenated lsdvd documents
+func TestParseMultiple(t *testing.T) {
+	xmlData := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<lsdvd>
+    <device>./s1d1</device>
+    <title>Disc One</title>
+    <track>
+        <ix>1</ix>
+        <length>100.0</length>
+        <df>Pan&Scan</df>
+    </track>
+    <longest_track>1</longest_track>
+</lsdvd>
+<?xml version="1.0" encoding="UTF-8"?>
+<lsdvd>
+    <device>./s1d2</device>
+    <title>Disc Two</title>
+    <track>
+        <ix>1</ix>
+        <length>100.0</length>
+    </track>
+    <track>
+        <ix>2</ix>
+        <length>200.0</length>
+    </track>
+    <longest_track>2</longest_track>
+</lsdvd>
+`)
+
+	dvds, err := ParseMultiple(xmlData)
+	if err != nil {
+		t.Fatalf("Failed to parse concatenated XML: %v", err)
+	}
+	if len(dvds) != 2 {
+		t.Fatalf("Expected 2 DVDs, got %d", len(dvds))
+	}
+
+	if dvds[0].Device != "./s1d1" || dvds[1].Device != "./s1d2" {
+		t.Errorf("Expected devices ./s1d1 and ./s1d2, got %s and %s", dvds[0].Device, dvds[1].Device)
+	}
+	if dvds[0].Tracks[0].DF != "Pan&Scan" {
+		t.Errorf("Expected DF 'Pan&Scan', got '%s'", dvds[0].Tracks[0].DF)
+	}
+	if len(dvds[1].Tracks) != 2 || dvds[1].LongestTrack != 2 {
+		t.Errorf("Expected 2 tracks and longest track 2 on the second disc, got %d and %d",
+			len(dvds[1].Tracks), dvds[1].LongestTrack)
+	}
+
+	if _, err := ParseMultiple([]byte("\n")); !errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("Expected ErrEmptyDocument for empty input, got %v", err)
+	}
+}
+
+// TestParseBytesVerbose tests that repairs and placeholders are reported
+func TestParseBytesVerbose(t *testing.T) {
+	xmlData := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<lsdvd>
+    <device>./test</device>
+    <title>unknown</title>
+    <track>
+        <ix>1</ix>
+        <df>Pan&Scan</df>
+    </track>
+</lsdvd>`)
+
+	dvd, warnings, err := ParseBytesVerbose(xmlData)
+	if err != nil {
+		t.Fatalf("ParseBytesVerbose failed: %v", err)
+	}
+	if dvd.Tracks[0].DF != "Pan&Scan" {
+		t.Errorf("Expected DF 'Pan&Scan', got '%s'", dvd.Tracks[0].DF)
+	}
+
+	expected := []string{
+		`escaped stray ampersand in <df> ("Pan&Scan")`,
+		`title is the lsdvd "unknown" placeholder`,
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expected %d warnings, got %d: %q", len(expected), len(warnings), warnings)
+	}
+	for i := range expected {
+		if warnings[i] != expected[i] {
+			t.Errorf("Warning %d: expected %q, got %q", i, expected[i], warnings[i])
+		}
+	}
+
+	// The quiet parser returns the same data
+	quiet, err := ParseBytes(xmlData)
+	if err != nil {
+		t.Fatalf("ParseBytes failed: %v", err)
+	}
+	if quiet.Title != dvd.Title || quiet.Tracks[0].DF != dvd.Tracks[0].DF {
+		t.Errorf("ParseBytes and ParseBytesVerbose disagree: %+v vs %+v", quiet, dvd)
+	}
+
+	clean := []byte(`<lsdvd><device>./test</device><title>SHOW</title></lsdvd>`)
+	if _, warnings, err := ParseBytesVerbose(clean); err != nil || len(warnings) != 0 {
+		t.Errorf("Expected no warnings for clean XML, got %q (err %v)", warnings, err)
+	}
+
+	if _, _, err := ParseBytesVerbose(nil); !errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("Expected ErrEmptyDocument for empty data, got %v", err)
+	}
+}
+
+// TestGetLongestTrackByIndex tests that the longest track is matched by index
+// rather than slice position
+func TestGetLongestTrackByIndex(t *testing.T) {
+	// Track 2 is missing, so slice position and index diverge after track 1
+	dvd := &DVD{
+		LongestTrack: 3,
+		Tracks: []Track{
+			{Index: 1, Length: 100},
+			{Index: 3, Length: 300},
+			{Index: 4, Length: 200},
+		},
+	}
+
+	if longest := dvd.GetLongestTrack(); longest == nil || longest.Index != 3 {
+		t.Errorf("Expected track 3, got %+v", longest)
+	}
+
+	// Without a matching index, fall back to the slice position
+	dvd.LongestTrack = 2
+	if longest := dvd.GetLongestTrack(); longest == nil || longest.Index != 3 {
+		t.Errorf("Expected fallback to second track (index 3), got %+v", longest)
+	}
+
+	dvd.LongestTrack = 0
+	if longest := dvd.GetLongestTrack(); longest != nil {
+		t.Errorf("Expected nil for LongestTrack 0, got %+v", longest)
+	}
+	dvd.LongestTrack = 9
+	if longest := dvd.GetLongestTrack(); longest != nil {
+		t.Errorf("Expected nil for LongestTrack 9, got %+v", longest)
+	}
+}
+
+// TestParseDecimalComma tests lengths written with a decimal comma
+func TestParseDecimalComma(t *testing.T) {
+	xmlData := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<lsdvd>
+    <device>./test</device>
+    <track>
+        <ix>1</ix>
+        <length>100,5</length>
+        <fps>25,00</fps>
+        <chapter>
+            <ix>1</ix>
+            <length>60,25</length>
+        </chapter>
+        <cell>
+            <ix>1</ix>
+            <length>40,25</length>
+        </cell>
+    </track>
+    <track>
+        <ix>2</ix>
+        <length>2500.56</length>
+    </track>
+</lsdvd>`)
+
+	dvd, err := ParseBytes(xmlData)
+	if err != nil {
+		t.Fatalf("ParseBytes failed: %v", err)
+	}
+
+	track := dvd.Tracks[0]
+	if track.Length != 100.5 {
+		t.Errorf("Expected track length 100.5, got %v", track.Length)
+	}
+	if track.FPS != 25 {
+		t.Errorf("Expected FPS 25, got %v", track.FPS)
+	}
+	if track.Chapters[0].Length != 60.25 {
+		t.Errorf("Expected chapter length 60.25, got %v", track.Chapters[0].Length)
+	}
+	if track.Cells[0].Length != 40.25 {
+		t.Errorf("Expected cell length 40.25, got %v", track.Cells[0].Length)
+	}
+	if dvd.Tracks[1].Length != 2500.56 {
+		t.Errorf("Expected dot-decimal length 2500.56 unchanged, got %v", dvd.Tracks[1].Length)
+	}
+
+	_, warnings, err := ParseBytesVerbose(xmlData)
+	if err != nil {
+		t.Fatalf("ParseBytesVerbose failed: %v", err)
+	}
+	if len(warnings) != 4 || warnings[0] != `replaced decimal comma in <length> ("100,5")` {
+		t.Errorf("Expected 4 decimal comma warnings, got %q", warnings)
+	}
+}
+
+// TestParseError tests that parse failures report the file and position
+func TestParseError(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "broken.xml")
+	content := "<lsdvd>\n    <device>./test</device>\n    <track><ix>1</ix></trak>\n</lsdvd>"
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", filename, err)
+	}
+
+	_, err := ParseFile(filename)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected *ParseError, got %T: %v", err, err)
+	}
+	if parseErr.File != filename {
+		t.Errorf("Expected file %s, got %s", filename, parseErr.File)
+	}
+	if parseErr.Offset == 0 {
+		t.Error("Expected a non-zero offset")
+	}
+	if parseErr.Line != 3 {
+		t.Errorf("Expected line 3, got %d", parseErr.Line)
+	}
+	var syntaxErr *xml.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected the underlying *xml.SyntaxError, got %v", parseErr.Err)
+	}
+	if !strings.Contains(err.Error(), filename) {
+		t.Errorf("Expected error to mention %s, got %q", filename, err.Error())
+	}
+
+	// Empty files are parse errors too, and still match ErrEmptyDocument
+	empty := filepath.Join(dir, "empty.xml")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", empty, err)
+	}
+	_, err = ParseFile(empty)
+	if !errors.As(err, &parseErr) || parseErr.File != empty || !errors.Is(err, ErrEmptyDocument) {
+		t.Errorf("Expected *ParseError wrapping ErrEmptyDocument for %s, got %v", empty, err)
+	}
+}
+
+// TestParseMultipleError tests that ParseMultiple reports the failing document
+func TestParseMultipleError(t *testing.T) {
+	data := []byte("<lsdvd>\n    <device>./one</device>\n</lsdvd>\n<lsdvd>\n    <track><ix>1</ix></trak>\n</lsdvd>")
+
+	_, err := ParseMultiple(data)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected *ParseError, got %T: %v", err, err)
+	}
+	if parseErr.Document != 2 {
+		t.Errorf("Expected document 2, got %d", parseErr.Document)
+	}
+	if parseErr.Line != 5 {
+		t.Errorf("Expected line 5, got %d", parseErr.Line)
+	}
+	if parseErr.Offset == 0 {
+		t.Error("Expected a non-zero offset")
+	}
+	var syntaxErr *xml.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected the underlying *xml.SyntaxError, got %v", parseErr.Err)
+	}
+	if !strings.Contains(err.Error(), "document 2") {
+		t.Errorf("Expected error to mention document 2, got %q", err.Error())
+	}
+}
@@ -0,0 +1,49 @@
+package dvd
+
+import (
+	"math"
+)
+
+// playAllTolerance is the relative difference allowed between a play-all
+// track's length and the total length of the episodes it contains
+const playAllTolerance = 0.02
+
+// GetPlayAllTracks returns the tracks that appear to be "play all" tracks:
+// tracks whose length matches the combined length of at least two other
+// tracks in the same title set (VTS), as on TV box sets where one long title
+// plays every episode on the disc.
+func (d *DVD) GetPlayAllTracks() []*Track {
+	return d.FilterTracksFunc(d.isPlayAll)
+}
+
+// isPlayAll reports whether track is the combination of other tracks in its title set
+func (d *DVD) isPlayAll(track *Track) bool {
+	if track.Length <= 0 {
+		return false
+	}
+
+	var sum float64
+	parts := 0
+	for i := range d.Tracks {
+		other := &d.Tracks[i]
+		if other == track || other.VTS != track.VTS || other.Length <= 0 || other.Length >= track.Length {
+			continue
+		}
+		sum += other.Length
+		parts++
+	}
+
+	return parts >= 2 && math.Abs(sum-track.Length) <= track.Length*playAllTolerance
+}
+
+// ContentDuration returns the total duration of all tracks in seconds,
+// excluding play-all tracks so that episodes are not counted twice
+func (d *DVD) ContentDuration() float64 {
+	var total float64
+	for i := range d.Tracks {
+		if !d.isPlayAll(&d.Tracks[i]) {
+			total += d.Tracks[i].Length
+		}
+	}
+	return total
+}
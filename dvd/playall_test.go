@@ -0,0 +1,66 @@
+package dvd
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// newBoxSetDVD returns a DVD laid out like a TV box set disc: four episodes
+// and a play-all track in one title set, plus short extras in other title sets
+func newBoxSetDVD() *DVD {
+	return &DVD{Tracks: []Track{
+		{Index: 1, VTS: 1, Length: 2500.56},
+		{Index: 2, VTS: 1, Length: 2459.88},
+		{Index: 3, VTS: 1, Length: 2457.64},
+		{Index: 4, VTS: 1, Length: 2441.76},
+		{Index: 5, VTS: 1, Length: 9857.28},
+		{Index: 6, VTS: 2, Length: 24.0},
+		{Index: 7, VTS: 3, Length: 14.0},
+	}}
+}
+
+// TestGetPlayAllTracks tests play-all track detection
+func TestGetPlayAllTracks(t *testing.T) {
+	dvd := newBoxSetDVD()
+	if got := trackIndexes(dvd.GetPlayAllTracks()); !equalInts(got, []int{5}) {
+		t.Errorf("Expected play-all tracks [5], got %v", got)
+	}
+
+	// One episode per title set, as on the season 2 discs, has no play-all track
+	separate := &DVD{Tracks: []Track{
+		{Index: 1, VTS: 1, Length: 2527.0},
+		{Index: 2, VTS: 2, Length: 2551.0},
+		{Index: 3, VTS: 3, Length: 5078.0},
+	}}
+	if got := trackIndexes(separate.GetPlayAllTracks()); !equalInts(got, []int{}) {
+		t.Errorf("Expected no play-all tracks, got %v", got)
+	}
+
+	// Every sample disc with a single title set of episodes has one
+	files, _ := filepath.Glob("../source/s1d*.xml")
+	for _, file := range files {
+		fixture, err := ParseFile(file)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", file, err)
+		}
+		if got := trackIndexes(fixture.GetPlayAllTracks()); !equalInts(got, []int{fixture.LongestTrack}) {
+			t.Errorf("%s: expected play-all tracks [%d], got %v", file, fixture.LongestTrack, got)
+		}
+	}
+}
+
+// TestContentDuration tests the total duration excluding play-all tracks
+func TestContentDuration(t *testing.T) {
+	dvd := newBoxSetDVD()
+	dvd.Tracks = dvd.Tracks[:5] // episodes and play-all only
+
+	expected := 2500.56 + 2459.88 + 2457.64 + 2441.76
+	if got := dvd.ContentDuration(); math.Abs(got-expected) > 1e-6 {
+		t.Errorf("Expected content duration %.2f, got %.2f", expected, got)
+	}
+
+	if got, total := dvd.ContentDuration(), dvd.GetTotalDuration(); got >= total {
+		t.Errorf("Expected content duration %.2f to be less than total %.2f", got, total)
+	}
+}
@@ -0,0 +1,40 @@
+package dvd
+
+import (
+	"strings"
+)
+
+// VideoStandard is the broadcast standard of a track's video
+type VideoStandard int
+
+// Video standards reported by lsdvd in the track format field
+const (
+	Unknown VideoStandard = iota
+	PAL
+	NTSC
+)
+
+// String returns the name of the video standard as lsdvd writes it
+func (vs VideoStandard) String() string {
+	switch vs {
+	case PAL:
+		return "PAL"
+	case NTSC:
+		return "NTSC"
+	default:
+		return "Unknown"
+	}
+}
+
+// VideoStandard returns the track's video standard parsed from its Format
+// field, compared case-insensitively
+func (t *Track) VideoStandard() VideoStandard {
+	switch strings.ToUpper(strings.TrimSpace(t.Format)) {
+	case "PAL":
+		return PAL
+	case "NTSC":
+		return NTSC
+	default:
+		return Unknown
+	}
+}
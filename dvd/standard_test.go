@@ -0,0 +1,31 @@
+package dvd
+
+import (
+	"testing"
+)
+
+// TestVideoStandard tests mapping the format string to a VideoStandard
+func TestVideoStandard(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected VideoStandard
+		name     string
+	}{
+		{"PAL", PAL, "PAL"},
+		{"ntsc", NTSC, "NTSC"},
+		{" Pal ", PAL, "PAL"},
+		{"SECAM", Unknown, "Unknown"},
+		{"", Unknown, "Unknown"},
+	}
+
+	for _, tc := range testCases {
+		track := Track{Format: tc.format}
+		vs := track.VideoStandard()
+		if vs != tc.expected {
+			t.Errorf("VideoStandard() for %q = %v, expected %v", tc.format, vs, tc.expected)
+		}
+		if vs.String() != tc.name {
+			t.Errorf("String() for %q = %q, expected %q", tc.format, vs.String(), tc.name)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package dvd
+
+import (
+	"math"
+	"sort"
+)
+
+// GetAverageTrackDuration returns the mean track length in seconds, or 0 if
+// the DVD has no tracks
+func (d *DVD) GetAverageTrackDuration() float64 {
+	if len(d.Tracks) == 0 {
+		return 0
+	}
+	return d.GetTotalDuration() / float64(len(d.Tracks))
+}
+
+// GetMedianTrackDuration returns the median track length in seconds, or 0 if
+// the DVD has no tracks. For an even number of tracks the two middle lengths
+// are averaged.
+func (d *DVD) GetMedianTrackDuration() float64 {
+	if len(d.Tracks) == 0 {
+		return 0
+	}
+
+	lengths := make([]float64, len(d.Tracks))
+	for i, track := range d.Tracks {
+		lengths[i] = track.Length
+	}
+	sort.Float64s(lengths)
+
+	mid := len(lengths) / 2
+	if len(lengths)%2 == 0 {
+		return (lengths[mid-1] + lengths[mid]) / 2
+	}
+	return lengths[mid]
+}
+
+// GetMostCommonDuration groups track lengths into buckets bucketSeconds wide,
+// centred on multiples of bucketSeconds, and returns the centre and size of the
+// most populated bucket. When several buckets are equally populated the
+// shortest one wins. It returns (0, 0) if the DVD has no tracks or
+// bucketSeconds is not positive.
+func (d *DVD) GetMostCommonDuration(bucketSeconds float64) (float64, int) {
+	if len(d.Tracks) == 0 || bucketSeconds <= 0 {
+		return 0, 0
+	}
+
+	counts := make(map[int64]int)
+	for _, track := range d.Tracks {
+		counts[int64(math.Round(track.Length/bucketSeconds))]++
+	}
+
+	var bestBucket int64
+	bestCount := 0
+	for bucket, count := range counts {
+		if count > bestCount || (count == bestCount && bucket < bestBucket) {
+			bestBucket = bucket
+			bestCount = count
+		}
+	}
+	return float64(bestBucket) * bucketSeconds, bestCount
+}
+
+// TotalChapters returns the number of chapters across all tracks
+func (d *DVD) TotalChapters() int {
+	total := 0
+	for _, track := range d.Tracks {
+		total += len(track.Chapters)
+	}
+	return total
+}
+
+// TotalAudioStreams returns the number of audio streams across all tracks
+func (d *DVD) TotalAudioStreams() int {
+	total := 0
+	for _, track := range d.Tracks {
+		total += len(track.AudioStreams)
+	}
+	return total
+}
+
+// TotalSubtitleStreams returns the number of subtitle streams across all tracks
+func (d *DVD) TotalSubtitleStreams() int {
+	total := 0
+	for _, track := range d.Tracks {
+		total += len(track.SubtitleStreams)
+	}
+	return total
+}
@@ -0,0 +1,98 @@
+package dvd
+
+import (
+	"math"
+	"testing"
+)
+
+// newLengthsDVD returns a DVD with one track per length
+func newLengthsDVD(lengths ...float64) *DVD {
+	dvd := &DVD{}
+	for i, length := range lengths {
+		dvd.Tracks = append(dvd.Tracks, Track{Index: i + 1, Length: length})
+	}
+	return dvd
+}
+
+// TestAverageAndMedianTrackDuration tests the track duration statistics
+func TestAverageAndMedianTrackDuration(t *testing.T) {
+	testCases := []struct {
+		description string
+		lengths     []float64
+		average     float64
+		median      float64
+	}{
+		{"odd count", []float64{2500, 30, 2460, 9800, 120}, 2982, 2460},
+		{"even count", []float64{2500, 30, 2460, 120}, 1277.5, 1290},
+		{"single track", []float64{42.5}, 42.5, 42.5},
+		{"empty disc", nil, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		dvd := newLengthsDVD(tc.lengths...)
+		if got := dvd.GetAverageTrackDuration(); math.Abs(got-tc.average) > 1e-9 {
+			t.Errorf("%s: expected average %.2f, got %.2f", tc.description, tc.average, got)
+		}
+		if got := dvd.GetMedianTrackDuration(); math.Abs(got-tc.median) > 1e-9 {
+			t.Errorf("%s: expected median %.2f, got %.2f", tc.description, tc.median, got)
+		}
+	}
+}
+
+// TestGetMostCommonDuration tests bucketed episode length detection
+func TestGetMostCommonDuration(t *testing.T) {
+	testCases := []struct {
+		description string
+		lengths     []float64
+		bucket      float64
+		center      float64
+		count       int
+	}{
+		{"episodes and a play-all", []float64{2400, 2410, 2395, 2402, 9800}, 120, 2400, 4},
+		{"single track", []float64{1300}, 120, 1320, 1},
+		{"even split picks shortest", []float64{2400, 1300, 2410, 1290}, 120, 1320, 2},
+		{"empty disc", nil, 120, 0, 0},
+		{"invalid bucket", []float64{2400}, 0, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		dvd := newLengthsDVD(tc.lengths...)
+		center, count := dvd.GetMostCommonDuration(tc.bucket)
+		if math.Abs(center-tc.center) > 1e-9 || count != tc.count {
+			t.Errorf("%s: expected (%.1f, %d), got (%.1f, %d)",
+				tc.description, tc.center, tc.count, center, count)
+		}
+	}
+}
+
+// TestTotalCounts tests the disc-wide chapter and stream counts
+func TestTotalCounts(t *testing.T) {
+	dvd := &DVD{Tracks: []Track{
+		{
+			Index:           1,
+			AudioStreams:    []AudioStream{{Index: 1}, {Index: 2}},
+			SubtitleStreams: []SubtitleStream{{Index: 1}, {Index: 2}, {Index: 3}},
+			Chapters:        []Chapter{{Index: 1}, {Index: 2}, {Index: 3}, {Index: 4}, {Index: 5}},
+		},
+		{
+			Index:        2,
+			AudioStreams: []AudioStream{{Index: 1}},
+			Chapters:     []Chapter{{Index: 1}, {Index: 2}},
+		},
+	}}
+
+	if got := dvd.TotalChapters(); got != 7 {
+		t.Errorf("Expected 7 chapters, got %d", got)
+	}
+	if got := dvd.TotalAudioStreams(); got != 3 {
+		t.Errorf("Expected 3 audio streams, got %d", got)
+	}
+	if got := dvd.TotalSubtitleStreams(); got != 3 {
+		t.Errorf("Expected 3 subtitle streams, got %d", got)
+	}
+
+	empty := &DVD{}
+	if empty.TotalChapters() != 0 || empty.TotalAudioStreams() != 0 || empty.TotalSubtitleStreams() != 0 {
+		t.Error("Expected zero counts for a DVD without tracks")
+	}
+}
@@ -0,0 +1,62 @@
+package dvd
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+)
+
+// DecodeStream decodes successive lsdvd documents from r and calls fn with each
+// one as soon as it has been read, so that large aggregated dumps can be
+// processed without holding every DVD in memory. Decoding stops at the end of
+// the stream or at the first error, including an error returned by fn. Parse
+// failures are returned as a *ParseError giving the document.
+//
+// The stream is not sanitized like ParseBytes. Instead the decoder runs in
+// non-strict mode, which keeps bare ampersands such as "Pan&Scan" as literal
+// text but is also more lenient with other malformed markup. Decimal commas in
+// <length> and <fps> are replaced line by line, which covers lsdvd output where
+// each element is on its own line.
+func DecodeStream(r io.Reader, fn func(*DVD) error) error {
+	decoder := xml.NewDecoder(&decimalCommaReader{r: bufio.NewReader(r)})
+	decoder.Strict = false
+
+	for count := 1; ; count++ {
+		var dvd DVD
+		err := decoder.Decode(&dvd)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			parseErr := newParseError(decoder, err)
+			parseErr.Document = count
+			return parseErr
+		}
+		if err := fn(&dvd); err != nil {
+			return err
+		}
+	}
+}
+
+// decimalCommaReader rewrites decimal commas in <length> and <fps> one line at
+// a time, so that a stream can be repaired without reading it all into memory
+type decimalCommaReader struct {
+	r   *bufio.Reader
+	buf []byte
+	err error
+}
+
+// Read implements io.Reader
+func (d *decimalCommaReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		var line []byte
+		line, d.err = d.r.ReadBytes('\n')
+		d.buf = decimalComma.ReplaceAll(line, []byte("<$1>$2.$3</"))
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,114 @@
+package dvd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestDecodeStream tests streaming decoding of concatenated documents
+func TestDecodeStream(t *testing.T) {
+	var sb strings.Builder
+	for i := 1; i <= 3; i++ {
+		sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<lsdvd>
+    <device>./disc</device>
+    <track>
+        <ix>1</ix>
+        <length>100.0</length>
+        <df>Pan&Scan</df>
+    </track>
+    <longest_track>1</longest_track>
+</lsdvd>
+`)
+	}
+
+	count := 0
+	err := DecodeStream(strings.NewReader(sb.String()), func(dvd *DVD) error {
+		count++
+		if len(dvd.Tracks) != 1 {
+			t.Errorf("Document %d: expected 1 track, got %d", count, len(dvd.Tracks))
+		} else if dvd.Tracks[0].DF != "Pan&Scan" {
+			t.Errorf("Document %d: expected DF 'Pan&Scan', got '%s'", count, dvd.Tracks[0].DF)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeStream failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 callbacks, got %d", count)
+	}
+
+	// An error from the callback stops decoding
+	errStop := errors.New("stop")
+	count = 0
+	err = DecodeStream(strings.NewReader(sb.String()), func(dvd *DVD) error {
+		count++
+		return errStop
+	})
+	if err != errStop {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 callback before stopping, got %d", count)
+	}
+}
+
+// TestDecodeStreamDecimalComma tests that decimal commas are repaired in streams
+func TestDecodeStreamDecimalComma(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<lsdvd>
+    <track>
+        <ix>1</ix>
+        <length>2500,56</length>
+        <fps>25,00</fps>
+    </track>
+    <longest_track>1</longest_track>
+</lsdvd>
+`
+	var tracks []Track
+	err := DecodeStream(strings.NewReader(doc+doc), func(dvd *DVD) error {
+		tracks = append(tracks, dvd.Tracks...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeStream failed: %v", err)
+	}
+	if len(tracks) != 2 {
+		t.Fatalf("Expected 2 tracks, got %d", len(tracks))
+	}
+	for i, track := range tracks {
+		if track.Length != 2500.56 {
+			t.Errorf("Track %d: expected length 2500.56, got %.2f", i, track.Length)
+		}
+		if track.FPS != 25.0 {
+			t.Errorf("Track %d: expected fps 25.00, got %.2f", i, track.FPS)
+		}
+	}
+}
+
+// TestDecodeStreamError tests that DecodeStream reports the failing document
+func TestDecodeStreamError(t *testing.T) {
+	// The decoder is not strict, so use a document truncated mid-track
+	stream := "<lsdvd>\n    <device>./one</device>\n</lsdvd>\n<lsdvd>\n    <track><ix>1</ix>\n"
+
+	count := 0
+	err := DecodeStream(strings.NewReader(stream), func(dvd *DVD) error {
+		count++
+		return nil
+	})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected *ParseError, got %T: %v", err, err)
+	}
+	if parseErr.Document != 2 {
+		t.Errorf("Expected document 2, got %d", parseErr.Document)
+	}
+	if parseErr.Line != 6 {
+		t.Errorf("Expected line 6, got %d", parseErr.Line)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 callback before the error, got %d", count)
+	}
+}
@@ -0,0 +1,17 @@
+package dvd
+
+import (
+	"strings"
+)
+
+// IsForced reports whether the stream's content marks it as forced subtitles,
+// shown only for foreign-language dialogue, compared case-insensitively
+func (s *SubtitleStream) IsForced() bool {
+	return strings.Contains(strings.ToLower(s.Content), "forced")
+}
+
+// StreamIDInt returns the stream's ID as an integer. The ID may be hex with a
+// "0x" prefix, as lsdvd writes it (e.g. "0x20"), or decimal.
+func (s *SubtitleStream) StreamIDInt() (int, error) {
+	return parseStreamID(s.StreamID)
+}
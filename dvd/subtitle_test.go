@@ -0,0 +1,27 @@
+package dvd
+
+import (
+	"testing"
+)
+
+// TestIsForced tests recognising forced subtitles from their content
+func TestIsForced(t *testing.T) {
+	testCases := []struct {
+		content  string
+		expected bool
+	}{
+		{"Forced", true},
+		{"FORCED", true},
+		{"Large Caption", false},
+		{"Director's Comments", false},
+		{"Normal Caption", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		sub := SubtitleStream{Content: tc.content}
+		if got := sub.IsForced(); got != tc.expected {
+			t.Errorf("IsForced() for %q = %v, expected %v", tc.content, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package dvd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetCellOffsets returns the start time in seconds of each cell in the track,
+// measured from the beginning of the track
+func (t *Track) GetCellOffsets() []float64 {
+	offsets := make([]float64, len(t.Cells))
+	var offset float64
+	for i, cell := range t.Cells {
+		offsets[i] = offset
+		offset += cell.Length
+	}
+	return offsets
+}
+
+// AspectRatio parses the track's aspect string (e.g. "4/3" or "16/9") and
+// returns it as a number
+func (t *Track) AspectRatio() (float64, error) {
+	parts := strings.SplitN(t.Aspect, "/", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid aspect ratio %q", t.Aspect)
+	}
+	num, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid aspect ratio %q: %v", t.Aspect, err)
+	}
+	den, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid aspect ratio %q: %v", t.Aspect, err)
+	}
+	if num <= 0 || den <= 0 {
+		return 0, fmt.Errorf("invalid aspect ratio %q", t.Aspect)
+	}
+	return num / den, nil
+}
+
+// IsWidescreen reports whether the track has an aspect ratio of 1.5 or wider
+func (t *Track) IsWidescreen() bool {
+	ratio, err := t.AspectRatio()
+	return err == nil && ratio >= 1.5
+}
+
+// IsMultiAngle reports whether the track offers more than one camera angle
+func (t *Track) IsMultiAngle() bool {
+	return t.Angles > 1
+}
+
+// DisplayResolution returns the square-pixel dimensions the track is shown
+// at, keeping the stored height and widening or narrowing the width to match
+// the aspect ratio, so anamorphic 720x576 video at 16/9 displays at 1024x576.
+// If the aspect ratio is unknown the stored resolution is returned.
+func (t *Track) DisplayResolution() (int, int) {
+	ratio, err := t.AspectRatio()
+	if err != nil || t.Height <= 0 {
+		return t.Width, t.Height
+	}
+	return int(math.Round(float64(t.Height) * ratio)), t.Height
+}
+
+// AudioLanguages returns the unique audio languages of the track, in stream order
+func (t *Track) AudioLanguages() []string {
+	languages := []string{}
+	seen := make(map[string]bool)
+	for _, audio := range t.AudioStreams {
+		if audio.Language != "" && !seen[audio.Language] {
+			seen[audio.Language] = true
+			languages = append(languages, audio.Language)
+		}
+	}
+	return languages
+}
+
+// SubtitleLanguages returns the unique subtitle languages of the track, in stream order
+func (t *Track) SubtitleLanguages() []string {
+	languages := []string{}
+	seen := make(map[string]bool)
+	for _, sub := range t.SubtitleStreams {
+		if sub.Language != "" && !seen[sub.Language] {
+			seen[sub.Language] = true
+			languages = append(languages, sub.Language)
+		}
+	}
+	return languages
+}
+
+// GetAudioLanguages returns the unique audio languages of the track, sorted
+// alphabetically. It is the per-track counterpart of DVD.GetAudioLanguages.
+func (t *Track) GetAudioLanguages() []string {
+	languages := t.AudioLanguages()
+	sort.Strings(languages)
+	return languages
+}
+
+// GetSubtitleLanguages returns the unique subtitle languages of the track,
+// sorted alphabetically. It is the per-track counterpart of DVD.GetSubtitleLanguages.
+func (t *Track) GetSubtitleLanguages() []string {
+	languages := t.SubtitleLanguages()
+	sort.Strings(languages)
+	return languages
+}
+
+// HasLanguage reports whether the track has an audio or subtitle stream whose
+// language code or name matches language case-insensitively, such as "ja" or
+// "Japanese"
+func (t *Track) HasLanguage(language string) bool {
+	if strings.TrimSpace(language) == "" {
+		return false
+	}
+	return t.GetAudioByLanguageCode(language) != nil || hasAudioLanguage(t, language) ||
+		t.GetSubtitleByLanguageCode(language) != nil || hasSubtitleLanguage(t, language)
+}
+
+// GetAudioFormats returns the unique audio formats of the track, such as
+// "ac3" and "dts", sorted alphabetically
+func (t *Track) GetAudioFormats() []string {
+	formats := []string{}
+	seen := make(map[string]bool)
+	for _, audio := range t.AudioStreams {
+		if audio.Format != "" && !seen[audio.Format] {
+			seen[audio.Format] = true
+			formats = append(formats, audio.Format)
+		}
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// GetAudioByLanguage returns the audio streams whose language name matches
+// language case-insensitively, in stream order
+func (t *Track) GetAudioByLanguage(language string) []*AudioStream {
+	streams := []*AudioStream{}
+	for i := range t.AudioStreams {
+		if strings.EqualFold(t.AudioStreams[i].Language, language) {
+			streams = append(streams, &t.AudioStreams[i])
+		}
+	}
+	return streams
+}
+
+// GetAudioByLanguageCode returns the first audio stream whose language code
+// matches code case-insensitively, or nil if there is none
+func (t *Track) GetAudioByLanguageCode(code string) *AudioStream {
+	for i := range t.AudioStreams {
+		if strings.EqualFold(t.AudioStreams[i].LanguageCode, code) {
+			return &t.AudioStreams[i]
+		}
+	}
+	return nil
+}
+
+// GetAudioByFormat returns the audio streams in the given format (e.g. "ac3"
+// or "dts"), compared case-insensitively, in stream order
+func (t *Track) GetAudioByFormat(format string) []*AudioStream {
+	streams := []*AudioStream{}
+	for i := range t.AudioStreams {
+		if strings.EqualFold(t.AudioStreams[i].Format, format) {
+			streams = append(streams, &t.AudioStreams[i])
+		}
+	}
+	return streams
+}
+
+// GetAudioAtIndex returns the audio stream with the given 1-based lsdvd
+// index, or nil if there is none
+func (t *Track) GetAudioAtIndex(ix int) *AudioStream {
+	for i := range t.AudioStreams {
+		if t.AudioStreams[i].Index == ix {
+			return &t.AudioStreams[i]
+		}
+	}
+	return nil
+}
+
+// GetSubtitleByLanguage returns the first subtitle stream whose language name
+// matches language case-insensitively, or nil if there is none
+func (t *Track) GetSubtitleByLanguage(language string) *SubtitleStream {
+	for i := range t.SubtitleStreams {
+		if strings.EqualFold(t.SubtitleStreams[i].Language, language) {
+			return &t.SubtitleStreams[i]
+		}
+	}
+	return nil
+}
+
+// GetSubtitleByLanguageCode returns the first subtitle stream whose language
+// code matches code case-insensitively, or nil if there is none
+func (t *Track) GetSubtitleByLanguageCode(code string) *SubtitleStream {
+	for i := range t.SubtitleStreams {
+		if strings.EqualFold(t.SubtitleStreams[i].LanguageCode, code) {
+			return &t.SubtitleStreams[i]
+		}
+	}
+	return nil
+}
+
+// GetSubtitleAtIndex returns the subtitle stream with the given 1-based lsdvd
+// index, or nil if there is none
+func (t *Track) GetSubtitleAtIndex(ix int) *SubtitleStream {
+	for i := range t.SubtitleStreams {
+		if t.SubtitleStreams[i].Index == ix {
+			return &t.SubtitleStreams[i]
+		}
+	}
+	return nil
+}
+
+// GetSubtitlesByContent returns the subtitle streams whose content type (e.g.
+// "Normal", "Large" or "Children") matches content case-insensitively, in
+// stream order
+func (t *Track) GetSubtitlesByContent(content string) []*SubtitleStream {
+	streams := []*SubtitleStream{}
+	for i := range t.SubtitleStreams {
+		if strings.EqualFold(t.SubtitleStreams[i].Content, content) {
+			streams = append(streams, &t.SubtitleStreams[i])
+		}
+	}
+	return streams
+}
+
+// GetForcedSubtitles returns the subtitle streams marked as forced, such as
+// "Forced" or "Forced CC" content, in stream order. Unlike
+// GetSubtitlesByContent, any content containing "forced" matches.
+func (t *Track) GetForcedSubtitles() []*SubtitleStream {
+	streams := []*SubtitleStream{}
+	for i := range t.SubtitleStreams {
+		if t.SubtitleStreams[i].IsForced() {
+			streams = append(streams, &t.SubtitleStreams[i])
+		}
+	}
+	return streams
+}
+
+// GetSubtitleContents returns the unique subtitle content types of the track,
+// such as "Forced" and "Normal", sorted alphabetically
+func (t *Track) GetSubtitleContents() []string {
+	contents := []string{}
+	seen := make(map[string]bool)
+	for _, sub := range t.SubtitleStreams {
+		if sub.Content != "" && !seen[sub.Content] {
+			seen[sub.Content] = true
+			contents = append(contents, sub.Content)
+		}
+	}
+	sort.Strings(contents)
+	return contents
+}
+
+// Audio application modes (ap_mode) reported by lsdvd
+const (
+	apModeNormal   = 0
+	apModeKaraoke  = 1
+	apModeSurround = 2
+)
+
+// PrimaryAudio returns the audio stream with the lowest index, or nil if the
+// track has no audio. DVDs have no explicit default flag, so karaoke streams
+// (ap_mode 1) are passed over unless nothing else is available.
+func (t *Track) PrimaryAudio() *AudioStream {
+	var primary *AudioStream
+	for i := range t.AudioStreams {
+		audio := &t.AudioStreams[i]
+		switch {
+		case primary == nil:
+			primary = audio
+		case primary.APMode == apModeKaraoke && audio.APMode != apModeKaraoke:
+			primary = audio
+		case (primary.APMode == apModeKaraoke) == (audio.APMode == apModeKaraoke) && audio.Index < primary.Index:
+			primary = audio
+		}
+	}
+	return primary
+}
+
+// FrameCount returns the number of video frames in the track, or 0 if the
+// frame rate is unknown
+func (t *Track) FrameCount() int64 {
+	return frameCount(t.Length, t.FPS)
+}
+
+// frameCount returns the number of frames in seconds of video at fps
+func frameCount(seconds, fps float64) int64 {
+	if fps <= 0 {
+		return 0
+	}
+	return int64(math.Round(seconds * fps))
+}
+
+// sectorSize is the size in bytes of a DVD sector
+const sectorSize = 2048
+
+// SizeBytes returns the size of the track computed from the cell sector
+// ranges, or 0 if the lsdvd output has no sector information
+func (t *Track) SizeBytes() int64 {
+	var sectors int64
+	for _, cell := range t.Cells {
+		if cell.LastSector >= cell.FirstSector && cell.LastSector > 0 {
+			sectors += cell.LastSector - cell.FirstSector + 1
+		}
+	}
+	return sectors * sectorSize
+}
+
+// EstimatedBitrateKbps returns the average bitrate of the track in kilobits
+// per second, estimated from its size and length. It returns 0 if the size
+// or length is unknown.
+func (t *Track) EstimatedBitrateKbps() int {
+	size := t.SizeBytes()
+	if size == 0 || t.Length <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(size) * 8 / t.Length / 1000))
+}
+
+// clone returns a deep copy of the track, so that its streams, chapters,
+// cells and palette can be changed without affecting t
+func (t *Track) clone() Track {
+	c := *t
+	c.Palette.Colors = append([]string(nil), t.Palette.Colors...)
+	c.AudioStreams = append([]AudioStream(nil), t.AudioStreams...)
+	c.SubtitleStreams = append([]SubtitleStream(nil), t.SubtitleStreams...)
+	c.Chapters = append([]Chapter(nil), t.Chapters...)
+	c.Cells = append([]Cell(nil), t.Cells...)
+	return c
+}
@@ -0,0 +1,526 @@
+package dvd
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestGetCellOffsets tests cumulative cell start time calculation
+func TestGetCellOffsets(t *testing.T) {
+	xmlData := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<lsdvd>
+    <device>./test</device>
+    <track>
+        <ix>1</ix>
+        <length>1551.540</length>
+        <cell>
+            <ix>1</ix>
+            <length>735.200</length>
+        </cell>
+        <cell>
+            <ix>2</ix>
+            <length>423.200</length>
+        </cell>
+        <cell>
+            <ix>3</ix>
+            <length>393.140</length>
+        </cell>
+    </track>
+    <track>
+        <ix>2</ix>
+        <length>10.0</length>
+    </track>
+    <longest_track>1</longest_track>
+</lsdvd>`)
+
+	dvd, err := ParseBytes(xmlData)
+	if err != nil {
+		t.Fatalf("Failed to parse XML: %v", err)
+	}
+
+	track := dvd.GetTrackByIndex(1)
+	offsets := track.GetCellOffsets()
+	if len(offsets) != len(track.Cells) {
+		t.Fatalf("Expected %d offsets, got %d", len(track.Cells), len(offsets))
+	}
+
+	expected := []float64{0, 735.2, 1158.4}
+	for i, want := range expected {
+		if math.Abs(offsets[i]-want) > 1e-9 {
+			t.Errorf("Expected offset %d to be %.3f, got %.3f", i, want, offsets[i])
+		}
+	}
+
+	var total float64
+	for _, cell := range track.Cells {
+		total += cell.Length
+	}
+	last := len(track.Cells) - 1
+	if end := offsets[last] + track.Cells[last].Length; math.Abs(end-total) > 1e-9 {
+		t.Errorf("Expected last cell to end at %.3f, got %.3f", total, end)
+	}
+
+	// A track without cells should return an empty slice
+	empty := dvd.GetTrackByIndex(2).GetCellOffsets()
+	if empty == nil || len(empty) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %v", empty)
+	}
+}
+
+// TestAspectRatio tests parsing of the aspect string
+func TestAspectRatio(t *testing.T) {
+	testCases := []struct {
+		aspect     string
+		expected   float64
+		widescreen bool
+		wantErr    bool
+	}{
+		{"4/3", 4.0 / 3.0, false, false},
+		{"16/9", 16.0 / 9.0, true, false},
+		{"16:9", 0, false, true},
+		{"a/b", 0, false, true},
+		{"4/0", 0, false, true},
+		{"", 0, false, true},
+	}
+
+	for _, tc := range testCases {
+		track := Track{Aspect: tc.aspect}
+		ratio, err := track.AspectRatio()
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("AspectRatio(%q): expected error, got %.3f", tc.aspect, ratio)
+			}
+		} else if err != nil {
+			t.Errorf("AspectRatio(%q): unexpected error: %v", tc.aspect, err)
+		} else if math.Abs(ratio-tc.expected) > 1e-9 {
+			t.Errorf("AspectRatio(%q) = %.3f, expected %.3f", tc.aspect, ratio, tc.expected)
+		}
+
+		if track.IsWidescreen() != tc.widescreen {
+			t.Errorf("IsWidescreen(%q) = %v, expected %v", tc.aspect, !tc.widescreen, tc.widescreen)
+		}
+	}
+}
+
+// TestTrackLanguages tests the per-track language lookups
+func TestTrackLanguages(t *testing.T) {
+	track := Track{
+		AudioStreams: []AudioStream{
+			{Index: 1, Language: "English"},
+			{Index: 2, Language: "Francais"},
+			{Index: 3, Language: "English"},
+			{Index: 4, Language: ""},
+		},
+		SubtitleStreams: []SubtitleStream{
+			{Index: 1, Language: "Nederlands"},
+			{Index: 2, Language: "English"},
+			{Index: 3, Language: "Nederlands"},
+		},
+	}
+
+	audio := track.AudioLanguages()
+	expectedAudio := []string{"English", "Francais"}
+	if strings.Join(audio, ",") != strings.Join(expectedAudio, ",") {
+		t.Errorf("Expected audio languages %v, got %v", expectedAudio, audio)
+	}
+
+	subs := track.SubtitleLanguages()
+	expectedSubs := []string{"Nederlands", "English"}
+	if strings.Join(subs, ",") != strings.Join(expectedSubs, ",") {
+		t.Errorf("Expected subtitle languages %v, got %v", expectedSubs, subs)
+	}
+
+	empty := Track{}
+	if langs := empty.AudioLanguages(); langs == nil || len(langs) != 0 {
+		t.Errorf("Expected empty audio languages, got %v", langs)
+	}
+}
+
+// TestPrimaryAudio tests selection of the default audio stream
+func TestPrimaryAudio(t *testing.T) {
+	track := Track{AudioStreams: []AudioStream{
+		{Index: 1, Language: "English"},
+		{Index: 2, Language: "Francais"},
+	}}
+	if audio := track.PrimaryAudio(); audio == nil || audio.Index != 1 {
+		t.Errorf("Expected audio stream 1, got %+v", audio)
+	}
+
+	// Stream order in the XML doesn't matter, the lowest index wins
+	track.AudioStreams[0].Index, track.AudioStreams[1].Index = 2, 1
+	if audio := track.PrimaryAudio(); audio == nil || audio.Language != "Francais" {
+		t.Errorf("Expected Francais audio stream, got %+v", audio)
+	}
+
+	karaoke := Track{AudioStreams: []AudioStream{
+		{Index: 1, Language: "English", APMode: 1},
+		{Index: 2, Language: "Francais"},
+	}}
+	if audio := karaoke.PrimaryAudio(); audio == nil || audio.Index != 2 {
+		t.Errorf("Expected non-karaoke audio stream 2, got %+v", audio)
+	}
+
+	empty := Track{}
+	if audio := empty.PrimaryAudio(); audio != nil {
+		t.Errorf("Expected nil for track without audio, got %+v", audio)
+	}
+}
+
+// TestFrameCount tests frame count calculation for tracks and chapters
+func TestFrameCount(t *testing.T) {
+	track := Track{Length: 100.0, FPS: 25.0}
+	if got := track.FrameCount(); got != 2500 {
+		t.Errorf("Expected 2500 frames, got %d", got)
+	}
+
+	ntsc := Track{Length: 1001.0, FPS: 30000.0 / 1001.0}
+	if got := ntsc.FrameCount(); got != 30000 {
+		t.Errorf("Expected 30000 frames, got %d", got)
+	}
+
+	unknown := Track{Length: 100.0}
+	if got := unknown.FrameCount(); got != 0 {
+		t.Errorf("Expected 0 frames without a frame rate, got %d", got)
+	}
+
+	chapter := Chapter{Length: 735.2}
+	if got := chapter.FrameCount(25.0); got != 18380 {
+		t.Errorf("Expected 18380 chapter frames, got %d", got)
+	}
+	if got := chapter.FrameCount(0); got != 0 {
+		t.Errorf("Expected 0 chapter frames without a frame rate, got %d", got)
+	}
+}
+
+// TestEstimatedBitrateKbps tests bitrate estimation from cell sectors
+func TestEstimatedBitrateKbps(t *testing.T) {
+	xmlData := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<lsdvd>
+    <device>./test</device>
+    <track>
+        <ix>1</ix>
+        <length>100.0</length>
+        <cell>
+            <ix>1</ix>
+            <length>60.0</length>
+            <first_sector>0</first_sector>
+            <last_sector>29999</last_sector>
+        </cell>
+        <cell>
+            <ix>2</ix>
+            <length>40.0</length>
+            <first_sector>30000</first_sector>
+            <last_sector>49999</last_sector>
+        </cell>
+    </track>
+    <track>
+        <ix>2</ix>
+        <length>100.0</length>
+        <cell>
+            <ix>1</ix>
+            <length>100.0</length>
+        </cell>
+    </track>
+    <longest_track>1</longest_track>
+</lsdvd>`)
+
+	dvd, err := ParseBytes(xmlData)
+	if err != nil {
+		t.Fatalf("Failed to parse XML: %v", err)
+	}
+
+	// 50000 sectors * 2048 bytes * 8 bits / 100 seconds / 1000 = 8192 kbps
+	track := dvd.GetTrackByIndex(1)
+	if size := track.SizeBytes(); size != 50000*2048 {
+		t.Errorf("Expected size %d bytes, got %d", 50000*2048, size)
+	}
+	if kbps := track.EstimatedBitrateKbps(); kbps != 8192 {
+		t.Errorf("Expected 8192 kbps, got %d", kbps)
+	}
+
+	if kbps := dvd.GetTrackByIndex(2).EstimatedBitrateKbps(); kbps != 0 {
+		t.Errorf("Expected 0 kbps without sector data, got %d", kbps)
+	}
+}
+
+// parseFixtureTrack parses s1d1.xml and returns the track with the given
+// index, skipping the test if the fixture is missing
+func parseFixtureTrack(t *testing.T, index int) *Track {
+	t.Helper()
+	testFile := "../source/s1d1.xml"
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		t.Skipf("Test file %s not found, skipping test", testFile)
+	}
+	dvd, err := ParseFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", testFile, err)
+	}
+	track := dvd.GetTrackByIndex(index)
+	if track == nil {
+		t.Fatalf("Track %d not found in %s", index, testFile)
+	}
+	return track
+}
+
+// TestGetAudioByLanguage tests audio stream lookup by language name and code
+func TestGetAudioByLanguage(t *testing.T) {
+	track := parseFixtureTrack(t, 1)
+
+	english := track.GetAudioByLanguage("english")
+	if len(english) != 1 || english[0].LanguageCode != "en" {
+		t.Errorf("Expected one English stream, got %+v", english)
+	}
+	if german := track.GetAudioByLanguage("Deutsch"); german == nil || len(german) != 0 {
+		t.Errorf("Expected no Deutsch streams, got %+v", german)
+	}
+
+	if french := track.GetAudioByLanguageCode("FR"); french == nil || french.Language != "Francais" {
+		t.Errorf("Expected Francais stream for code FR, got %+v", french)
+	}
+	if german := track.GetAudioByLanguageCode("de"); german != nil {
+		t.Errorf("Expected nil for code de, got %+v", german)
+	}
+
+	// Streams sharing a language are all returned
+	dual := Track{AudioStreams: []AudioStream{
+		{Index: 1, Language: "English"},
+		{Index: 2, Language: "Francais"},
+		{Index: 3, Language: "English", Content: "Comments1"},
+	}}
+	if got := dual.GetAudioByLanguage("English"); len(got) != 2 || got[0].Index != 1 || got[1].Index != 3 {
+		t.Errorf("Expected English streams 1 and 3, got %+v", got)
+	}
+}
+
+// TestGetAudioByFormat tests audio stream lookup by codec
+func TestGetAudioByFormat(t *testing.T) {
+	track := parseFixtureTrack(t, 1)
+
+	ac3 := track.GetAudioByFormat("AC3")
+	if len(ac3) != 2 || ac3[0].Index != 1 || ac3[1].Index != 2 {
+		t.Errorf("Expected ac3 streams 1 and 2, got %+v", ac3)
+	}
+	if dts := track.GetAudioByFormat("dts"); dts == nil || len(dts) != 0 {
+		t.Errorf("Expected no dts streams, got %+v", dts)
+	}
+}
+
+// TestGetAudioAtIndex tests audio stream lookup by lsdvd index
+func TestGetAudioAtIndex(t *testing.T) {
+	track := Track{AudioStreams: []AudioStream{
+		{Index: 1, Language: "English"},
+		{Index: 2, Language: "Francais"},
+	}}
+
+	if audio := track.GetAudioAtIndex(1); audio == nil || audio.Language != "English" {
+		t.Errorf("Expected English stream at index 1, got %+v", audio)
+	}
+	if audio := track.GetAudioAtIndex(2); audio == nil || audio.Language != "Francais" {
+		t.Errorf("Expected Francais stream at index 2, got %+v", audio)
+	}
+	for _, ix := range []int{0, 99} {
+		if audio := track.GetAudioAtIndex(ix); audio != nil {
+			t.Errorf("Expected nil at index %d, got %+v", ix, audio)
+		}
+	}
+}
+
+// TestGetSubtitleLookups tests subtitle stream lookup by language, code and index
+func TestGetSubtitleLookups(t *testing.T) {
+	track := parseFixtureTrack(t, 1)
+
+	// Francais appears twice; the first stream wins
+	if sub := track.GetSubtitleByLanguage("francais"); sub == nil || sub.Index != 2 {
+		t.Errorf("Expected Francais subtitle 2, got %+v", sub)
+	}
+	if sub := track.GetSubtitleByLanguage("Deutsch"); sub != nil {
+		t.Errorf("Expected nil for Deutsch, got %+v", sub)
+	}
+
+	if sub := track.GetSubtitleByLanguageCode("NL"); sub == nil || sub.Language != "Nederlands" {
+		t.Errorf("Expected Nederlands subtitle for code NL, got %+v", sub)
+	}
+	if sub := track.GetSubtitleByLanguageCode("de"); sub != nil {
+		t.Errorf("Expected nil for code de, got %+v", sub)
+	}
+
+	if sub := track.GetSubtitleAtIndex(4); sub == nil || sub.LanguageCode != "fr" {
+		t.Errorf("Expected fr subtitle at index 4, got %+v", sub)
+	}
+	if sub := track.GetSubtitleAtIndex(0); sub != nil {
+		t.Errorf("Expected nil at index 0, got %+v", sub)
+	}
+}
+
+// TestDisplayResolution tests aspect-corrected display dimensions
+func TestDisplayResolution(t *testing.T) {
+	testCases := []struct {
+		width, height  int
+		aspect         string
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{720, 576, "4/3", 768, 576},
+		{720, 576, "16/9", 1024, 576},
+		{720, 480, "16/9", 853, 480},
+		{720, 576, "", 720, 576},
+		{0, 0, "16/9", 0, 0},
+	}
+
+	for _, tc := range testCases {
+		track := Track{Width: tc.width, Height: tc.height, Aspect: tc.aspect}
+		w, h := track.DisplayResolution()
+		if w != tc.expectedWidth || h != tc.expectedHeight {
+			t.Errorf("DisplayResolution() for %dx%d at %q = %dx%d, expected %dx%d",
+				tc.width, tc.height, tc.aspect, w, h, tc.expectedWidth, tc.expectedHeight)
+		}
+	}
+}
+
+// TestGetSubtitlesByContent tests subtitle filtering by content type
+func TestGetSubtitlesByContent(t *testing.T) {
+	track := Track{SubtitleStreams: []SubtitleStream{
+		{Index: 1, Content: "Normal"},
+		{Index: 2, Content: "Large"},
+		{Index: 3, Content: "Children"},
+		{Index: 4, Content: "normal"},
+	}}
+
+	testCases := []struct {
+		content  string
+		expected []int
+	}{
+		{"Normal", []int{1, 4}},
+		{"LARGE", []int{2}},
+		{"children", []int{3}},
+		{"Director", []int{}},
+	}
+
+	for _, tc := range testCases {
+		subs := track.GetSubtitlesByContent(tc.content)
+		got := []int{}
+		for _, sub := range subs {
+			got = append(got, sub.Index)
+		}
+		if subs == nil || !equalInts(got, tc.expected) {
+			t.Errorf("GetSubtitlesByContent(%q) = %v, expected %v", tc.content, got, tc.expected)
+		}
+	}
+}
+
+// TestGetForcedSubtitles tests finding forced subtitle streams
+func TestGetForcedSubtitles(t *testing.T) {
+	track := Track{SubtitleStreams: []SubtitleStream{
+		{Index: 1, Content: "Normal"},
+		{Index: 2, Content: "Forced CC"},
+		{Index: 3, Content: "FORCED"},
+	}}
+
+	forced := track.GetForcedSubtitles()
+	if len(forced) != 2 || forced[0].Index != 2 || forced[1].Index != 3 {
+		t.Errorf("Expected forced subtitles 2 and 3, got %+v", forced)
+	}
+
+	normal := Track{SubtitleStreams: []SubtitleStream{{Index: 1, Content: "Normal"}}}
+	if forced := normal.GetForcedSubtitles(); forced == nil || len(forced) != 0 {
+		t.Errorf("Expected no forced subtitles, got %+v", forced)
+	}
+}
+
+// TestHasLanguage tests language lookup across audio and subtitle streams
+func TestHasLanguage(t *testing.T) {
+	track := Track{
+		AudioStreams:    []AudioStream{{Index: 1, LanguageCode: "ja", Language: "Japanese"}},
+		SubtitleStreams: []SubtitleStream{{Index: 1, LanguageCode: "en", Language: "English"}},
+	}
+
+	testCases := []struct {
+		language string
+		expected bool
+	}{
+		{"ja", true},
+		{"en", true},
+		{"JA", true},
+		{"japanese", true},
+		{"English", true},
+		{"fr", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		if got := track.HasLanguage(tc.language); got != tc.expected {
+			t.Errorf("HasLanguage(%q) = %v, expected %v", tc.language, got, tc.expected)
+		}
+	}
+}
+
+// TestGetAudioFormats tests listing unique audio formats
+func TestGetAudioFormats(t *testing.T) {
+	testCases := []struct {
+		formats  []string
+		expected []string
+	}{
+		{[]string{"ac3", "ac3"}, []string{"ac3"}},
+		{[]string{"dts", "ac3", "dts"}, []string{"ac3", "dts"}},
+		{[]string{""}, []string{}},
+		{nil, []string{}},
+	}
+
+	for _, tc := range testCases {
+		var track Track
+		for i, format := range tc.formats {
+			track.AudioStreams = append(track.AudioStreams, AudioStream{Index: i + 1, Format: format})
+		}
+		got := track.GetAudioFormats()
+		if got == nil || strings.Join(got, ",") != strings.Join(tc.expected, ",") || len(got) != len(tc.expected) {
+			t.Errorf("GetAudioFormats() for %v = %v, expected %v", tc.formats, got, tc.expected)
+		}
+	}
+}
+
+// TestGetTrackLanguagesSorted tests the sorted per-track language lists
+func TestGetTrackLanguagesSorted(t *testing.T) {
+	track := Track{
+		AudioStreams: []AudioStream{
+			{Index: 1, Language: "Francais"},
+			{Index: 2, Language: "English"},
+			{Index: 3, Language: "Francais", Content: "Comments1"},
+		},
+		SubtitleStreams: []SubtitleStream{
+			{Index: 1, Language: "Nederlands"},
+			{Index: 2, Language: "English"},
+			{Index: 3, Language: "Nederlands"},
+			{Index: 4, Language: ""},
+		},
+	}
+
+	if got := strings.Join(track.GetAudioLanguages(), ","); got != "English,Francais" {
+		t.Errorf("Expected audio languages English,Francais, got %s", got)
+	}
+	if got := strings.Join(track.GetSubtitleLanguages(), ","); got != "English,Nederlands" {
+		t.Errorf("Expected subtitle languages English,Nederlands, got %s", got)
+	}
+
+	// The stream-order helpers are not affected
+	if got := strings.Join(track.AudioLanguages(), ","); got != "Francais,English" {
+		t.Errorf("Expected AudioLanguages in stream order, got %s", got)
+	}
+}
+
+// TestGetSubtitleContents tests listing unique subtitle content types
+func TestGetSubtitleContents(t *testing.T) {
+	track := Track{SubtitleStreams: []SubtitleStream{
+		{Index: 1, Content: "Normal"},
+		{Index: 2, Content: "Forced"},
+		{Index: 3, Content: "Normal"},
+	}}
+
+	if got := strings.Join(track.GetSubtitleContents(), ","); got != "Forced,Normal" {
+		t.Errorf("Expected contents Forced,Normal, got %s", got)
+	}
+	if got := (&Track{}).GetSubtitleContents(); got == nil || len(got) != 0 {
+		t.Errorf("Expected no contents for a track without subtitles, got %v", got)
+	}
+}
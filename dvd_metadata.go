@@ -2,24 +2,31 @@ package main
 
 import (
 	"dvd-metadata-parser/dvd"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
-// printDVDSummary prints a summary of the DVD metadata
-func printDVDSummary(filename string, dvdData *dvd.DVD) {
+// printDVDSummary prints a summary of the DVD metadata, followed by warning
+// about the declared longest track when it is not empty
+func printDVDSummary(filename string, dvdData *dvd.DVD, warning string) {
 	fmt.Printf("\n=== %s ===\n", filename)
 	fmt.Printf("Device: %s\n", dvdData.Device)
 	fmt.Printf("Title: %s\n", dvdData.Title)
 	fmt.Printf("Provider ID: %s\n", dvdData.ProviderID)
 	fmt.Printf("Number of tracks: %d\n", len(dvdData.Tracks))
 	fmt.Printf("Longest track: %d\n", dvdData.LongestTrack)
+	if warning != "" {
+		fmt.Printf("Warning: %s\n", warning)
+	}
 
 	for i, track := range dvdData.Tracks {
 		fmt.Printf("\n  Track %d:\n", track.Index)
-		fmt.Printf("    Length: %.2f seconds (%.2f minutes)\n", track.Length, track.Length/60)
+		fmt.Printf("    Length: %.2f seconds (%s)\n", track.Length, dvd.FormatDuration(track.Length))
 		fmt.Printf("    Resolution: %dx%d\n", track.Width, track.Height)
 		fmt.Printf("    Aspect: %s\n", track.Aspect)
 		fmt.Printf("    Format: %s @ %.2f fps\n", track.Format, track.FPS)
@@ -61,6 +68,21 @@ func printDVDSummary(filename string, dvdData *dvd.DVD) {
 	}
 }
 
+// longestTrackWarning describes what is wrong with the longest track lsdvd
+// declared: that no track has its index, or that another track is longer. It
+// returns an empty string when the declaration is correct or there are no tracks.
+func longestTrackWarning(dvdData *dvd.DVD) string {
+	longest := dvdData.GetTopNLongestTracks(1)
+	if len(longest) == 0 || dvdData.LongestTrackConsistent() {
+		return ""
+	}
+	if dvdData.GetTrackByIndex(dvdData.LongestTrack) == nil {
+		return fmt.Sprintf("declared longest track %d does not exist", dvdData.LongestTrack)
+	}
+	return fmt.Sprintf("declared longest track %d is not the longest, track %d is",
+		dvdData.LongestTrack, longest[0].Index)
+}
+
 // printDetailedTrackInfo prints detailed information about a specific track
 func printDetailedTrackInfo(track dvd.Track) {
 	fmt.Printf("\n--- Detailed Track %d Information ---\n", track.Index)
@@ -88,23 +110,81 @@ func printDetailedTrackInfo(track dvd.Track) {
 	}
 }
 
-// generateFFmpegCommand generates an FFmpeg command to extract a track or chapter
-func generateFFmpegCommand(match dvd.ContentMatch, dvdPath, outputPrefix string) string {
+// selectDetailedTracks returns the tracks to show detailed info for: the
+// longest track when longest is set, and the track with index trackIndex when
+// it is positive. It returns an error if trackIndex doesn't exist.
+func selectDetailedTracks(dvdData *dvd.DVD, longest bool, trackIndex int) ([]*dvd.Track, error) {
+	var tracks []*dvd.Track
+	if longest {
+		if longestTrack := dvdData.GetLongestTrack(); longestTrack != nil {
+			tracks = append(tracks, longestTrack)
+		}
+	}
+	if trackIndex > 0 {
+		track := dvdData.GetTrackByIndex(trackIndex)
+		if track == nil {
+			return tracks, fmt.Errorf("track %d not found", trackIndex)
+		}
+		if len(tracks) == 0 || tracks[0] != track {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks, nil
+}
+
+// generateFFmpegCommand generates an FFmpeg command to extract a track or chapter.
+// An angle above 1 selects that camera angle on multi-angle tracks; 0 or 1
+// uses the default angle.
+func generateFFmpegCommand(match dvd.ContentMatch, dvdPath, outputPrefix string, angle int) (string, error) {
+	angles := match.Track.Angles
+	if angles < 1 {
+		angles = 1
+	}
+	if angle < 1 {
+		return "", fmt.Errorf("invalid angle %d, angles are numbered from 1", angle)
+	}
+	if angle > angles {
+		return "", fmt.Errorf("track %d has %d angle(s), angle %d requested", match.Track.Index, angles, angle)
+	}
+
+	angleOption := ""
+	if angles > 1 && angle > 1 {
+		angleOption = fmt.Sprintf(" -angle %d", angle)
+	}
+
 	if match.Type == "track" {
 		// Extract entire track using dvdvideo demuxer
 		outputFile := fmt.Sprintf("%s_track_%02d.mkv", outputPrefix, match.Track.Index)
 		// Use dvdvideo:path and specify the title (track) to extract
-		return fmt.Sprintf("ffmpeg -f dvdvideo -i '%s' -title %d -map 0 -c copy %q",
-			dvdPath, match.Track.Index, outputFile)
+		return fmt.Sprintf("ffmpeg -f dvdvideo -i '%s' -title %d%s -map 0 -c copy %q",
+			dvdPath, match.Track.Index, angleOption, outputFile), nil
 	} else {
 		// Extract specific chapter range - this is more complex and would need chapter timing
 		outputFile := fmt.Sprintf("%s_track_%02d_chapter_%02d.mkv",
 			outputPrefix, match.Track.Index, match.Chapter.Index)
-		return fmt.Sprintf("ffmpeg -f dvdvideo -i '%s' -title %d -chapter_start %d -chapter_end %d -map 0 -c copy %q",
-			dvdPath, match.Track.Index, match.Chapter.Index, match.Chapter.Index+1, outputFile)
+		return fmt.Sprintf("ffmpeg -f dvdvideo -i '%s' -title %d%s -chapter_start %d -chapter_end %d -map 0 -c copy %q",
+			dvdPath, match.Track.Index, angleOption, match.Chapter.Index, match.Chapter.Index+1, outputFile), nil
 	}
 }
 
+// mainFeatureCommand generates an FFmpeg command to extract the main feature:
+// the track lsdvd declared as longest, or the actually longest track when the
+// declaration is wrong
+func mainFeatureCommand(dvdData *dvd.DVD, dvdPath, outputPrefix string, angle int) (string, error) {
+	track := dvdData.GetLongestTrack()
+	if !dvdData.LongestTrackConsistent() {
+		if longest := dvdData.GetTopNLongestTracks(1); len(longest) > 0 {
+			track = longest[0]
+		}
+	}
+	if track == nil {
+		return "", fmt.Errorf("no tracks to extract")
+	}
+
+	match := dvd.ContentMatch{Type: "track", Track: track, Duration: track.Length}
+	return generateFFmpegCommand(match, dvdPath, outputPrefix, angle)
+}
+
 // extractDVDPath tries to extract the DVD path from device string
 func extractDVDPath(device string) string {
 	// Remove common prefixes like "./" and get just the directory name
@@ -115,16 +195,20 @@ func extractDVDPath(device string) string {
 }
 
 // findEpisodeContent finds tracks and chapters around a specified duration
-func findEpisodeContent(filename string, dvdData *dvd.DVD, targetMinutes, toleranceMinutes float64) {
+// and returns the number of matches. When quiet is set the search range is
+// not announced.
+func findEpisodeContent(filename string, dvdData *dvd.DVD, targetMinutes, toleranceMinutes float64, quiet bool) int {
 	fmt.Printf("\n=== %s - ~%.0f Minute Content ===\n", filename, targetMinutes)
-	fmt.Printf("Looking for content between %.1f-%.1f minutes...\n",
-		targetMinutes-toleranceMinutes, targetMinutes+toleranceMinutes)
+	if !quiet {
+		fmt.Printf("Looking for content between %.1f-%.1f minutes...\n",
+			targetMinutes-toleranceMinutes, targetMinutes+toleranceMinutes)
+	}
 
 	matches := dvdData.FindContentAroundDuration(targetMinutes, toleranceMinutes)
 
 	if len(matches) == 0 {
 		fmt.Printf("  No tracks or chapters found around %.0f minutes.\n", targetMinutes)
-		return
+		return 0
 	}
 
 	tracksFound := 0
@@ -134,8 +218,8 @@ func findEpisodeContent(filename string, dvdData *dvd.DVD, targetMinutes, tolera
 	for _, match := range matches {
 		if match.Type == "track" {
 			tracksFound++
-			fmt.Printf("\n  ✓ Track %d: %.2f minutes (%.2f seconds)\n",
-				match.Track.Index, match.Duration/60, match.Duration)
+			fmt.Printf("\n  ✓ Track %d: %s (%.2f seconds)\n",
+				match.Track.Index, dvd.FormatDuration(match.Duration), match.Duration)
 			fmt.Printf("    Resolution: %dx%d, Format: %s @ %.2f fps\n",
 				match.Track.Width, match.Track.Height, match.Track.Format, match.Track.FPS)
 			fmt.Printf("    Audio: %d streams, Subtitles: %d streams, Chapters: %d\n",
@@ -145,25 +229,229 @@ func findEpisodeContent(filename string, dvdData *dvd.DVD, targetMinutes, tolera
 			if match.Track.Index != currentTrack {
 				currentTrack = match.Track.Index
 				fmt.Printf("\n  Track %d chapters:\n", match.Track.Index)
-				fmt.Printf("    Track length: %.2f minutes, Resolution: %dx%d\n",
-					match.Track.Length/60, match.Track.Width, match.Track.Height)
+				fmt.Printf("    Track length: %s, Resolution: %dx%d\n",
+					dvd.FormatDuration(match.Track.Length), match.Track.Width, match.Track.Height)
 			}
-			fmt.Printf("    ✓ Chapter %d: %.2f minutes (%.2f seconds)\n",
-				match.Chapter.Index, match.Duration/60, match.Duration)
+			fmt.Printf("    ✓ Chapter %d: %s (%.2f seconds)\n",
+				match.Chapter.Index, dvd.FormatDuration(match.Duration), match.Duration)
 		}
 	}
 
 	fmt.Printf("\nSummary: %d tracks and %d chapters found around %.0f minutes.\n",
 		tracksFound, chaptersFound, targetMinutes)
+	return len(matches)
+}
+
+// episodeResult holds the episode matches for a single file in JSON output
+type episodeResult struct {
+	File    string
+	Matches []dvd.ContentMatch
+}
+
+// writeJSON parses the XML files and writes them to w as indented JSON. When
+// targetMinutes is positive, the episode matches are written instead of the
+// full metadata, and the total number of matches is returned. Tracks shorter
+// than minMinutes are left out. A single value is written unless asArray is
+// set. Parse errors are reported to errw so they don't corrupt the JSON stream.
+func writeJSON(w, errw io.Writer, xmlFiles []string, asArray bool, targetMinutes, toleranceMinutes, minMinutes float64) (int, error) {
+	results := make([]interface{}, 0, len(xmlFiles))
+	totalMatches := 0
+	for _, xmlFile := range xmlFiles {
+		dvdData, err := dvd.ParseFile(xmlFile)
+		if err != nil {
+			fmt.Fprintf(errw, "Error parsing %s: %v\n", xmlFile, err)
+			continue
+		}
+		dvdData = applyMinLength(dvdData, minMinutes)
+
+		if targetMinutes > 0 {
+			matches := dvdData.FindContentAroundDuration(targetMinutes, toleranceMinutes)
+			if matches == nil {
+				matches = []dvd.ContentMatch{}
+			}
+			totalMatches += len(matches)
+			results = append(results, episodeResult{
+				File:    filepath.Base(xmlFile),
+				Matches: matches,
+			})
+		} else {
+			results = append(results, dvdData)
+		}
+	}
+
+	var output interface{} = results
+	if !asArray {
+		if len(results) == 0 {
+			return 0, fmt.Errorf("no metadata to write")
+		}
+		output = results[0]
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return totalMatches, encoder.Encode(output)
+}
+
+// outputFormats are the renderers accepted by -format
+var outputFormats = []string{"text", "json", "csv", "nfo"}
+
+// resolveFormat validates the -format value and applies the older -csv and
+// -json flags, which select their format when -format is left as text. The
+// -catalog flag resolves to "catalog" and can't be combined with another
+// format selector; neither can -csv and -json, or a contradicting -format.
+func resolveFormat(format string, csvOutput, jsonOut, catalog bool) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	valid := false
+	for _, f := range outputFormats {
+		if format == f {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return "", fmt.Errorf("unknown format %q (valid formats: %s)", format, strings.Join(outputFormats, ", "))
+	}
+
+	switch {
+	case catalog && (csvOutput || jsonOut || format != "text"):
+		return "", fmt.Errorf("-catalog cannot be combined with -format, -csv or -json")
+	case csvOutput && jsonOut:
+		return "", fmt.Errorf("-csv and -json cannot be combined")
+	case csvOutput && format != "text" && format != "csv":
+		return "", fmt.Errorf("-csv conflicts with -format %s", format)
+	case jsonOut && format != "text" && format != "json":
+		return "", fmt.Errorf("-json conflicts with -format %s", format)
+	case catalog:
+		return "catalog", nil
+	case csvOutput:
+		return "csv", nil
+	case jsonOut:
+		return "json", nil
+	}
+	return format, nil
+}
+
+// writeNFO writes a movie NFO for each XML file to w, one after another,
+// skipping files whose declared longest track is shorter than minMinutes.
+// Files that can't be parsed or described are reported to errw and skipped.
+func writeNFO(w, errw io.Writer, xmlFiles []string, minMinutes float64) error {
+	for _, xmlFile := range xmlFiles {
+		dvdData, err := dvd.ParseFile(xmlFile)
+		if err != nil {
+			fmt.Fprintf(errw, "Error parsing %s: %v\n", xmlFile, err)
+			continue
+		}
+		// The NFO describes the declared longest track of the whole disc, so
+		// the minimum only decides whether that track is written
+		if feature := dvdData.GetLongestTrack(); feature != nil && minMinutes > 0 && !dvd.MinLength(minMinutes*60)(feature) {
+			fmt.Fprintf(errw, "Error: %s: main feature is shorter than %.0f minutes\n", filepath.Base(xmlFile), minMinutes)
+			continue
+		}
+		nfo, err := dvdData.ToMovieNFO()
+		if err != nil {
+			fmt.Fprintf(errw, "Error: %s: %v\n", filepath.Base(xmlFile), err)
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", nfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseFiles parses the XML files into a map keyed by base filename, with
+// tracks shorter than minMinutes removed. Files that can't be parsed are
+// reported to errw and skipped.
+func parseFiles(errw io.Writer, xmlFiles []string, minMinutes float64) map[string]*dvd.DVD {
+	files := make(map[string]*dvd.DVD)
+	for _, xmlFile := range xmlFiles {
+		dvdData, err := dvd.ParseFile(xmlFile)
+		if err != nil {
+			fmt.Fprintf(errw, "Error parsing %s: %v\n", xmlFile, err)
+			continue
+		}
+		files[filepath.Base(xmlFile)] = applyMinLength(dvdData, minMinutes)
+	}
+	return files
+}
+
+// printBanner writes the "Found N XML files" banner to w unless quiet is set
+func printBanner(w io.Writer, fileCount int, quiet bool) {
+	if !quiet {
+		fmt.Fprintf(w, "Found %d XML files to process\n", fileCount)
+	}
+}
+
+// applyMinLength returns the DVD with tracks shorter than minMinutes removed.
+// LongestTrack keeps the track lsdvd declared, even if it was removed, so the
+// output still shows the declaration. A minMinutes of 0 or less returns the
+// DVD unchanged.
+func applyMinLength(dvdData *dvd.DVD, minMinutes float64) *dvd.DVD {
+	if minMinutes <= 0 {
+		return dvdData
+	}
+	filtered := dvdData.ApplyFilter(dvd.MinLength(minMinutes * 60))
+	filtered.LongestTrack = dvdData.LongestTrack
+	return filtered
+}
+
+// prepareSummary returns what the text summary shows for a parsed DVD: the
+// DVD with tracks shorter than minMinutes removed, the warning about its
+// declared longest track, and the tracks to show in detail. The warning and
+// detailed tracks come from the whole disc, so -min-length doesn't hide them.
+func prepareSummary(dvdData *dvd.DVD, minMinutes float64, longest bool, trackIndex int) (*dvd.DVD, string, []*dvd.Track, error) {
+	tracks, err := selectDetailedTracks(dvdData, longest, trackIndex)
+	return applyMinLength(dvdData, minMinutes), longestTrackWarning(dvdData), tracks, err
+}
+
+// Exit codes
+const (
+	exitError     = 1 // invalid arguments or unreadable input
+	exitNoMatches = 2 // -episodes found no matching content in any file
+)
+
+// exitStatus returns the exit code after processing the XML files: exitError
+// when none of them could be parsed or the track requested with -track was in
+// none of them, exitNoMatches when an episode search found no matches, and 0
+// otherwise
+func exitStatus(parsedFiles int, trackMissing, searched bool, matches int) int {
+	switch {
+	case parsedFiles == 0, trackMissing:
+		return exitError
+	case searched && matches == 0:
+		return exitNoMatches
+	}
+	return 0
+}
+
+// countTrackMatches returns the number of whole-track matches, which are the
+// only matches that -ffmpeg generates commands for
+func countTrackMatches(matches []dvd.ContentMatch) int {
+	count := 0
+	for _, match := range matches {
+		if match.Type == "track" {
+			count++
+		}
+	}
+	return count
 }
 
 func main() {
 	// Define command line flags
 	var (
 		detailed  = flag.Bool("detailed", false, "Show detailed info for longest track")
+		trackNum  = flag.Int("track", 0, "Show detailed info for the track with this index")
 		episodes  = flag.Float64("episodes", 0, "Find tracks/chapters around specified duration in minutes (e.g., 40)")
 		tolerance = flag.Float64("tolerance", 5.0, "Tolerance in minutes for episode duration matching (default: 5)")
+		minLength = flag.Float64("min-length", 0, "Only include tracks at least this many minutes long, in every output mode (default: 0, all tracks)")
 		ffmpeg    = flag.Bool("ffmpeg", false, "Generate FFmpeg commands to extract episodes (use with -episodes)")
+		mainFeat  = flag.Bool("main-feature", false, "Generate a single FFmpeg command extracting the longest track")
+		angle     = flag.Int("angle", 1, "Camera angle to extract on multi-angle tracks (use with -ffmpeg)")
+		format    = flag.String("format", "text", "Output format: "+strings.Join(outputFormats, ", "))
+		csvOutput = flag.Bool("csv", false, "Write one CSV row per track to stdout (same as -format csv)")
+		catalog   = flag.Bool("catalog", false, "Write every file as one JSON array of filename and metadata objects to stdout")
+		jsonOut   = flag.Bool("json", false, "Write parsed metadata (or episode matches with -episodes) as JSON to stdout (same as -format json)")
+		quiet     = flag.Bool("quiet", false, "Suppress informational messages, printing only results")
 		showHelp  = flag.Bool("help", false, "Show this help message")
 	) // Custom usage function
 	flag.Usage = func() {
@@ -174,9 +462,21 @@ func main() {
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s source/s1d1.xml                    # Basic summary\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -detailed source                   # Show detailed longest track info\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -track 3 source/s1d1.xml           # Show detailed info for track 3\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -episodes 40 source                # Find ~40 minute episodes\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -episodes 22 -tolerance 3 source   # Find ~22 minute episodes (±3 min)\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -episodes 40 -ffmpeg source        # Generate FFmpeg commands for extraction\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -main-feature source/s1d1.xml      # FFmpeg command for the longest track only\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -min-length 1 source               # Hide tracks shorter than a minute\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -quiet source/s1d1.xml             # Summary without the banner\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -csv source > tracks.csv           # Export all tracks as CSV\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -format nfo source/s1d1.xml        # Kodi/Plex NFO for the main feature\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -catalog source > catalog.json     # Export all discs as one JSON array\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -json -episodes 40 source          # Episode matches as JSON\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0  Success\n")
+		fmt.Fprintf(os.Stderr, "  %d  Error (invalid arguments or unreadable input)\n", exitError)
+		fmt.Fprintf(os.Stderr, "  %d  No matching content found with -episodes\n", exitNoMatches)
 	}
 
 	// Parse command line flags
@@ -192,7 +492,14 @@ func main() {
 	if flag.NArg() != 1 {
 		fmt.Fprintf(os.Stderr, "Error: Please specify exactly one source directory or XML file\n\n")
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+
+	outputFormat, err := resolveFormat(*format, *csvOutput, *jsonOut, *catalog)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		flag.Usage()
+		os.Exit(exitError)
 	}
 
 	sourcePath := flag.Arg(0)
@@ -200,8 +507,8 @@ func main() {
 	// Check if the argument is a directory or a file
 	info, err := os.Stat(sourcePath)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitError)
 	}
 
 	var xmlFiles []string
@@ -211,8 +518,8 @@ func main() {
 		pattern := filepath.Join(sourcePath, "*.xml")
 		xmlFiles, err = filepath.Glob(pattern)
 		if err != nil {
-			fmt.Printf("Error finding XML files: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error finding XML files: %v\n", err)
+			os.Exit(exitError)
 		}
 	} else {
 		// Process single file
@@ -220,49 +527,121 @@ func main() {
 	}
 
 	if len(xmlFiles) == 0 {
-		fmt.Printf("No XML files found in %s\n", sourcePath)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "No XML files found in %s\n", sourcePath)
+		os.Exit(exitError)
 	}
 
-	// Only show processing message in non-FFmpeg mode
-	if !(*episodes > 0 && *ffmpeg) {
-		fmt.Printf("Found %d XML files to process\n", len(xmlFiles))
+	switch outputFormat {
+	case "catalog":
+		// Catalog mode: only output the JSON catalog, report parse errors on stderr
+		if err := dvd.WriteCatalogJSON(os.Stdout, parseFiles(os.Stderr, xmlFiles, *minLength)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing catalog: %v\n", err)
+			os.Exit(exitError)
+		}
+		return
+	case "csv":
+		// CSV mode: only output CSV, report parse errors on stderr
+		if err := dvd.WriteTracksCSV(os.Stdout, parseFiles(os.Stderr, xmlFiles, *minLength)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing CSV: %v\n", err)
+			os.Exit(exitError)
+		}
+		return
+	case "nfo":
+		// NFO mode: only output NFO documents, report errors on stderr
+		if err := writeNFO(os.Stdout, os.Stderr, xmlFiles, *minLength); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing NFO: %v\n", err)
+			os.Exit(exitError)
+		}
+		return
+	case "json":
+		// JSON mode: only output JSON, report errors on stderr
+		totalMatches, err := writeJSON(os.Stdout, os.Stderr, xmlFiles, info.IsDir(), *episodes, *tolerance, *minLength)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+			os.Exit(exitError)
+		}
+		if *episodes > 0 && totalMatches == 0 {
+			os.Exit(exitNoMatches)
+		}
+		return
+	}
+
+	if *mainFeat {
+		// Main feature mode: only output one FFmpeg command per file
+		for _, xmlFile := range xmlFiles {
+			dvdData, err := dvd.ParseFile(xmlFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", xmlFile, err)
+				continue
+			}
+			dvdData = applyMinLength(dvdData, *minLength)
+			name := filepath.Base(xmlFile)
+			outputPrefix := fmt.Sprintf("%s_main_feature", strings.TrimSuffix(name, filepath.Ext(name)))
+			cmd, err := mainFeatureCommand(dvdData, extractDVDPath(dvdData.Device), outputPrefix, *angle)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+				continue
+			}
+			fmt.Println(cmd)
+		}
+		return
 	}
 
+	// FFmpeg mode only outputs commands, so it is always quiet
+	printBanner(os.Stdout, len(xmlFiles), *quiet || (*episodes > 0 && *ffmpeg))
+
+	totalMatches := 0
+	parsedFiles := 0
+	trackFound := false
 	for _, xmlFile := range xmlFiles {
 		dvdData, err := dvd.ParseFile(xmlFile)
 		if err != nil {
-			fmt.Printf("Error parsing %s: %v\n", xmlFile, err)
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", xmlFile, err)
 			continue
 		}
+		parsedFiles++
 
 		if *episodes > 0 {
+			dvdData = applyMinLength(dvdData, *minLength)
 			if *ffmpeg {
-				// FFmpeg mode: only output commands
+				// FFmpeg mode: only output commands, which are generated for
+				// track matches alone, so only those count as matches
 				matches := dvdData.FindContentAroundDuration(*episodes, *tolerance)
+				totalMatches += countTrackMatches(matches)
 				if len(matches) > 0 {
 					dvdPath := extractDVDPath(dvdData.Device)
 					outputPrefix := fmt.Sprintf("%s_episodes", filepath.Base(xmlFile)[:len(filepath.Base(xmlFile))-4])
 					for _, match := range matches {
 						if match.Type == "track" {
-							cmd := generateFFmpegCommand(match, dvdPath, outputPrefix)
+							cmd, err := generateFFmpegCommand(match, dvdPath, outputPrefix, *angle)
+							if err != nil {
+								fmt.Fprintf(os.Stderr, "Error: %s: %v\n", filepath.Base(xmlFile), err)
+								continue
+							}
 							fmt.Println(cmd)
 						}
 					}
 				}
 			} else {
-				findEpisodeContent(filepath.Base(xmlFile), dvdData, *episodes, *tolerance)
+				totalMatches += findEpisodeContent(filepath.Base(xmlFile), dvdData, *episodes, *tolerance, *quiet)
 			}
 		} else {
-			printDVDSummary(filepath.Base(xmlFile), dvdData)
+			summary, warning, tracks, err := prepareSummary(dvdData, *minLength, *detailed, *trackNum)
+			printDVDSummary(filepath.Base(xmlFile), summary, warning)
 
-			// If detailed mode is enabled, show detailed info for the longest track
-			if *detailed {
-				longestTrack := dvdData.GetLongestTrack()
-				if longestTrack != nil {
-					printDetailedTrackInfo(*longestTrack)
-				}
+			// Show detailed info for the longest track and/or the selected track
+			for _, track := range tracks {
+				printDetailedTrackInfo(*track)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", filepath.Base(xmlFile), err)
+			} else if *trackNum > 0 {
+				trackFound = true
 			}
 		}
 	}
+
+	if code := exitStatus(parsedFiles, *trackNum > 0 && *episodes <= 0 && !trackFound, *episodes > 0, totalMatches); code != 0 {
+		os.Exit(code)
+	}
 }
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"bytes"
 	"dvd-metadata-parser/dvd"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -407,7 +409,10 @@ func TestFFmpegCommandGeneration(t *testing.T) {
 
 	match := matches[0]
 	dvdPath := extractDVDPath(dvdData.Device)
-	cmd := generateFFmpegCommand(match, dvdPath, "test_episodes")
+	cmd, err := generateFFmpegCommand(match, dvdPath, "test_episodes", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate FFmpeg command: %v", err)
+	}
 
 	// Validate the command contains expected elements
 	if !strings.Contains(cmd, "ffmpeg") {
@@ -471,7 +476,10 @@ func TestFFmpegModeOutput(t *testing.T) {
 	for _, match := range matches {
 		if match.Type == "track" {
 			dvdPath := extractDVDPath(dvdData.Device)
-			cmd := generateFFmpegCommand(match, dvdPath, "test")
+			cmd, err := generateFFmpegCommand(match, dvdPath, "test", 1)
+			if err != nil {
+				t.Fatalf("Failed to generate FFmpeg command: %v", err)
+			}
 			if !strings.Contains(cmd, "ffmpeg") {
 				t.Error("Command should contain ffmpeg")
 			}
@@ -481,3 +489,494 @@ func TestFFmpegModeOutput(t *testing.T) {
 		}
 	}
 }
+
+// TestJSONOutput tests that JSON mode writes valid JSON and nothing else
+func TestJSONOutput(t *testing.T) {
+	testFile := "source/s1d1.xml"
+
+	// Check if test file exists
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		t.Skipf("Test file %s not found, skipping test", testFile)
+	}
+
+	// Single file produces a single object
+	var stdout, stderr bytes.Buffer
+	if _, err := writeJSON(&stdout, &stderr, []string{testFile}, false, 0, 5.0, 0); err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+
+	var dvdData dvd.DVD
+	if err := json.Unmarshal(stdout.Bytes(), &dvdData); err != nil {
+		t.Fatalf("Output is not a valid DVD JSON object: %v", err)
+	}
+	if dvdData.Device != "./s1d1/Law And Order Svu" {
+		t.Errorf("Expected device './s1d1/Law And Order Svu', got '%s'", dvdData.Device)
+	}
+	if len(dvdData.Tracks) != 10 {
+		t.Errorf("Expected 10 tracks, got %d", len(dvdData.Tracks))
+	}
+
+	// Episode mode with a missing file produces an array and reports errors on stderr
+	stdout.Reset()
+	stderr.Reset()
+	files := []string{testFile, "source/does-not-exist.xml"}
+	totalMatches, err := writeJSON(&stdout, &stderr, files, true, 40.0, 5.0, 0)
+	if err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+
+	var results []episodeResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("Output is not a valid JSON array: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].File != "s1d1.xml" {
+		t.Errorf("Expected file 's1d1.xml', got '%s'", results[0].File)
+	}
+	if len(results[0].Matches) == 0 {
+		t.Error("Expected episode matches for s1d1.xml")
+	}
+	if totalMatches != len(results[0].Matches) {
+		t.Errorf("Expected %d total matches, got %d", len(results[0].Matches), totalMatches)
+	}
+	if !strings.Contains(stderr.String(), "does-not-exist.xml") {
+		t.Errorf("Expected parse error on stderr, got %q", stderr.String())
+	}
+}
+
+// TestSelectDetailedTracks tests choosing tracks for detailed output
+func TestSelectDetailedTracks(t *testing.T) {
+	dvdData := &dvd.DVD{
+		LongestTrack: 2,
+		Tracks: []dvd.Track{
+			{Index: 1, Length: 100.0},
+			{Index: 2, Length: 200.0},
+			{Index: 3, Length: 50.0},
+		},
+	}
+
+	testCases := []struct {
+		longest    bool
+		trackIndex int
+		expected   []int
+		wantErr    bool
+	}{
+		{true, 0, []int{2}, false},
+		{false, 3, []int{3}, false},
+		{true, 3, []int{2, 3}, false},
+		{true, 2, []int{2}, false},
+		{false, 0, nil, false},
+		{false, 99, nil, true},
+		{true, 99, []int{2}, true},
+	}
+
+	for _, tc := range testCases {
+		tracks, err := selectDetailedTracks(dvdData, tc.longest, tc.trackIndex)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("selectDetailedTracks(%v, %d) error = %v, wantErr %v", tc.longest, tc.trackIndex, err, tc.wantErr)
+		}
+		var got []int
+		for _, track := range tracks {
+			got = append(got, track.Index)
+		}
+		if len(got) != len(tc.expected) {
+			t.Errorf("selectDetailedTracks(%v, %d) = %v, expected %v", tc.longest, tc.trackIndex, got, tc.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.expected[i] {
+				t.Errorf("selectDetailedTracks(%v, %d) = %v, expected %v", tc.longest, tc.trackIndex, got, tc.expected)
+				break
+			}
+		}
+	}
+}
+
+// TestFFmpegCommandAngles tests angle selection in FFmpeg commands
+func TestFFmpegCommandAngles(t *testing.T) {
+	single := dvd.ContentMatch{Type: "track", Track: &dvd.Track{Index: 1, Angles: 1}}
+	cmd, err := generateFFmpegCommand(single, "s1d1", "test", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate FFmpeg command: %v", err)
+	}
+	if strings.Contains(cmd, "-angle") {
+		t.Errorf("Single-angle command should not select an angle: %s", cmd)
+	}
+	if _, err := generateFFmpegCommand(single, "s1d1", "test", 2); err == nil {
+		t.Error("Expected error requesting angle 2 of a single-angle track")
+	}
+
+	multi := dvd.ContentMatch{Type: "track", Track: &dvd.Track{Index: 3, Angles: 3}}
+	cmd, err = generateFFmpegCommand(multi, "s1d1", "test", 2)
+	if err != nil {
+		t.Fatalf("Failed to generate FFmpeg command: %v", err)
+	}
+	if !strings.Contains(cmd, "-title 3 -angle 2 ") {
+		t.Errorf("Multi-angle command should select angle 2: %s", cmd)
+	}
+	cmd, err = generateFFmpegCommand(multi, "s1d1", "test", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate FFmpeg command: %v", err)
+	}
+	if strings.Contains(cmd, "-angle") {
+		t.Errorf("Default angle should not be selected explicitly: %s", cmd)
+	}
+	if _, err := generateFFmpegCommand(multi, "s1d1", "test", 4); err == nil {
+		t.Error("Expected error requesting angle 4 of a three-angle track")
+	}
+	for _, invalid := range []int{0, -1} {
+		if _, err := generateFFmpegCommand(multi, "s1d1", "test", invalid); err == nil {
+			t.Errorf("Expected error requesting angle %d", invalid)
+		}
+	}
+}
+
+// TestApplyMinLength tests hiding short tracks with -min-length
+func TestApplyMinLength(t *testing.T) {
+	dvdData := &dvd.DVD{
+		LongestTrack: 2,
+		Tracks: []dvd.Track{
+			{Index: 1, Length: 30.0},
+			{Index: 2, Length: 2400.0},
+		},
+	}
+
+	filtered := applyMinLength(dvdData, 1)
+	if len(filtered.Tracks) != 1 || filtered.Tracks[0].Index != 2 {
+		t.Fatalf("Expected only track 2, got %+v", filtered.Tracks)
+	}
+	if filtered.LongestTrack != 2 {
+		t.Errorf("Expected longest track 2, got %d", filtered.LongestTrack)
+	}
+	if len(dvdData.Tracks) != 2 {
+		t.Errorf("Original DVD should keep both tracks, got %d", len(dvdData.Tracks))
+	}
+
+	if unfiltered := applyMinLength(dvdData, 0); len(unfiltered.Tracks) != 2 {
+		t.Errorf("Expected both tracks with no minimum, got %d", len(unfiltered.Tracks))
+	}
+
+	matches := applyMinLength(dvdData, 1).FindContentAroundDuration(40, 5)
+	if len(matches) != 1 || matches[0].Track.Index != 2 {
+		t.Errorf("Expected episode search to match only track 2, got %+v", matches)
+	}
+}
+
+// TestMinLengthKeepsDeclaredLongest tests that -min-length doesn't change the
+// declared longest track shown in the summary, -detailed and NFO output
+func TestMinLengthKeepsDeclaredLongest(t *testing.T) {
+	dvdData := &dvd.DVD{
+		LongestTrack: 2,
+		Tracks: []dvd.Track{
+			{Index: 1, Length: 3000.0},
+			{Index: 2, Length: 2400.0},
+			{Index: 3, Length: 30.0},
+		},
+	}
+
+	summary, warning, tracks, err := prepareSummary(dvdData, 1, true, 0)
+	if err != nil {
+		t.Fatalf("prepareSummary failed: %v", err)
+	}
+	if summary.LongestTrack != 2 {
+		t.Errorf("Expected declared longest track 2, got %d", summary.LongestTrack)
+	}
+	if len(summary.Tracks) != 2 {
+		t.Errorf("Expected 2 tracks of at least a minute, got %d", len(summary.Tracks))
+	}
+	if expected := "declared longest track 2 is not the longest, track 1 is"; warning != expected {
+		t.Errorf("Expected warning %q, got %q", expected, warning)
+	}
+	if len(tracks) != 1 || tracks[0].Index != 2 {
+		t.Errorf("Expected -detailed to show track 2, got %+v", tracks)
+	}
+
+	// A declared longest track removed by the filter is still reported as declared
+	_, warning, tracks, _ = prepareSummary(dvdData, 45, true, 0)
+	if expected := "declared longest track 2 is not the longest, track 1 is"; warning != expected {
+		t.Errorf("Expected warning %q, got %q", expected, warning)
+	}
+	if len(tracks) != 1 || tracks[0].Index != 2 {
+		t.Errorf("Expected -detailed to show track 2, got %+v", tracks)
+	}
+
+	xmlFile := filepath.Join(t.TempDir(), "disc.xml")
+	content := `<lsdvd>
+    <track><ix>1</ix><length>3000.0</length></track>
+    <track><ix>2</ix><length>2400.0</length></track>
+    <track><ix>3</ix><length>30.0</length></track>
+    <longest_track>2</longest_track>
+</lsdvd>`
+	if err := os.WriteFile(xmlFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", xmlFile, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := writeNFO(&stdout, &stderr, []string{xmlFile}, 1); err != nil {
+		t.Fatalf("writeNFO failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "<runtime>40</runtime>") {
+		t.Errorf("Expected the NFO to describe track 2, got %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := writeNFO(&stdout, &stderr, []string{xmlFile}, 45); err != nil {
+		t.Fatalf("writeNFO failed: %v", err)
+	}
+	if stdout.Len() != 0 || !strings.Contains(stderr.String(), "shorter than 45 minutes") {
+		t.Errorf("Expected no NFO when track 2 is too short, got %q and %q", stdout.String(), stderr.String())
+	}
+}
+
+// TestQuietBanner tests that -quiet suppresses the file count banner
+func TestQuietBanner(t *testing.T) {
+	var buf bytes.Buffer
+	printBanner(&buf, 3, false)
+	if buf.String() != "Found 3 XML files to process\n" {
+		t.Errorf("Expected banner, got %q", buf.String())
+	}
+
+	buf.Reset()
+	printBanner(&buf, 3, true)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output in quiet mode, got %q", buf.String())
+	}
+}
+
+// TestParseFiles tests parsing files for the CSV and catalog exports
+func TestParseFiles(t *testing.T) {
+	testFile := "source/s1d1.xml"
+
+	// Check if test file exists
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		t.Skipf("Test file %s not found, skipping test", testFile)
+	}
+
+	var stderr bytes.Buffer
+	files := parseFiles(&stderr, []string{testFile, "source/does-not-exist.xml"}, 0)
+	if len(files) != 1 || files["s1d1.xml"] == nil {
+		t.Errorf("Expected only s1d1.xml to be parsed, got %v", files)
+	}
+	if !strings.Contains(stderr.String(), "does-not-exist.xml") {
+		t.Errorf("Expected parse error on stderr, got %q", stderr.String())
+	}
+}
+
+// TestResolveFormat tests -format validation and the -csv and -json shortcuts
+func TestResolveFormat(t *testing.T) {
+	testCases := []struct {
+		format    string
+		csvOutput bool
+		jsonOut   bool
+		catalog   bool
+		expected  string
+	}{
+		{"text", false, false, false, "text"},
+		{"JSON", false, false, false, "json"},
+		{"nfo", false, false, false, "nfo"},
+		{"text", true, false, false, "csv"},
+		{"text", false, true, false, "json"},
+		{"csv", true, false, false, "csv"},
+		{"json", false, true, false, "json"},
+		{"text", false, false, true, "catalog"},
+	}
+
+	for _, tc := range testCases {
+		got, err := resolveFormat(tc.format, tc.csvOutput, tc.jsonOut, tc.catalog)
+		if err != nil {
+			t.Errorf("resolveFormat(%q, %v, %v, %v) failed: %v", tc.format, tc.csvOutput, tc.jsonOut, tc.catalog, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("resolveFormat(%q, %v, %v, %v) = %q, expected %q", tc.format, tc.csvOutput, tc.jsonOut, tc.catalog, got, tc.expected)
+		}
+	}
+
+	_, err := resolveFormat("yaml", false, false, false)
+	if err == nil {
+		t.Fatal("Expected error for unknown format")
+	}
+	if !strings.Contains(err.Error(), "text, json, csv, nfo") {
+		t.Errorf("Expected error to list valid formats, got %v", err)
+	}
+
+	// Contradicting format selectors are rejected
+	conflicts := []struct {
+		format    string
+		csvOutput bool
+		jsonOut   bool
+		catalog   bool
+	}{
+		{"nfo", false, true, false},
+		{"nfo", true, false, false},
+		{"json", true, false, false},
+		{"csv", false, true, false},
+		{"text", true, true, false},
+		{"nfo", false, false, true},
+		{"json", false, false, true},
+		{"text", true, false, true},
+		{"text", false, true, true},
+	}
+
+	for _, tc := range conflicts {
+		if got, err := resolveFormat(tc.format, tc.csvOutput, tc.jsonOut, tc.catalog); err == nil {
+			t.Errorf("resolveFormat(%q, %v, %v, %v) = %q, expected an error", tc.format, tc.csvOutput, tc.jsonOut, tc.catalog, got)
+		}
+	}
+}
+
+// TestMainFeatureCommand tests extracting only the longest track
+func TestMainFeatureCommand(t *testing.T) {
+	dvdData := &dvd.DVD{
+		LongestTrack: 2,
+		Tracks: []dvd.Track{
+			{Index: 1, Length: 100.0},
+			{Index: 2, Length: 5400.0},
+			{Index: 3, Length: 50.0},
+		},
+	}
+
+	cmd, err := mainFeatureCommand(dvdData, "movie", "movie_main_feature", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate main feature command: %v", err)
+	}
+	if !strings.Contains(cmd, "-f dvdvideo") || !strings.Contains(cmd, "-c copy") {
+		t.Errorf("Command should use the dvdvideo demuxer with stream copy: %s", cmd)
+	}
+	if !strings.Contains(cmd, "-title 2 ") || !strings.Contains(cmd, "movie_main_feature_track_02.mkv") {
+		t.Errorf("Command should extract track 2: %s", cmd)
+	}
+
+	// A wrong declaration falls back to the actually longest track
+	dvdData.LongestTrack = 1
+	cmd, err = mainFeatureCommand(dvdData, "movie", "movie_main_feature", 1)
+	if err != nil {
+		t.Fatalf("Failed to generate main feature command: %v", err)
+	}
+	if !strings.Contains(cmd, "-title 2 ") {
+		t.Errorf("Command should extract the computed longest track 2: %s", cmd)
+	}
+
+	if _, err := mainFeatureCommand(&dvd.DVD{}, "movie", "movie_main_feature", 1); err == nil {
+		t.Error("Expected error for a disc without tracks")
+	}
+}
+
+// TestWriteJSONMinLength tests that JSON output honours -min-length
+func TestWriteJSONMinLength(t *testing.T) {
+	testFile := "source/s1d1.xml"
+
+	// Check if test file exists
+	if _, err := os.Stat(testFile); os.IsNotExist(err) {
+		t.Skipf("Test file %s not found, skipping test", testFile)
+	}
+
+	// Full metadata: s1d1.xml has five tracks of at least a minute
+	var stdout, stderr bytes.Buffer
+	if _, err := writeJSON(&stdout, &stderr, []string{testFile}, false, 0, 5.0, 1); err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+	var dvdData dvd.DVD
+	if err := json.Unmarshal(stdout.Bytes(), &dvdData); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(dvdData.Tracks) != 5 {
+		t.Errorf("Expected 5 tracks of at least a minute, got %d", len(dvdData.Tracks))
+	}
+
+	// Episode search: the 21-24 second extras match only without a minimum
+	stdout.Reset()
+	totalMatches, err := writeJSON(&stdout, &stderr, []string{testFile}, false, 0.4, 0.1, 0)
+	if err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+	if totalMatches == 0 {
+		t.Fatal("Expected short extras to match without a minimum length")
+	}
+
+	stdout.Reset()
+	totalMatches, err = writeJSON(&stdout, &stderr, []string{testFile}, false, 0.4, 0.1, 1)
+	if err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+	var result episodeResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if totalMatches != 0 || len(result.Matches) != 0 {
+		t.Errorf("Expected no matches with a one minute minimum, got %d", totalMatches)
+	}
+}
+
+// TestLongestTrackWarning tests the summary warning for a wrong longest track
+func TestLongestTrackWarning(t *testing.T) {
+	testCases := []struct {
+		longestTrack int
+		expected     string
+	}{
+		{2, ""},
+		{1, "declared longest track 1 is not the longest, track 2 is"},
+		{0, "declared longest track 0 does not exist"},
+		{7, "declared longest track 7 does not exist"},
+	}
+
+	for _, tc := range testCases {
+		dvdData := &dvd.DVD{
+			LongestTrack: tc.longestTrack,
+			Tracks: []dvd.Track{
+				{Index: 1, Length: 100.0},
+				{Index: 2, Length: 200.0},
+			},
+		}
+		if got := longestTrackWarning(dvdData); got != tc.expected {
+			t.Errorf("longestTrackWarning() with longest track %d = %q, expected %q", tc.longestTrack, got, tc.expected)
+		}
+	}
+
+	if got := longestTrackWarning(&dvd.DVD{}); got != "" {
+		t.Errorf("Expected no warning for a DVD without tracks, got %q", got)
+	}
+}
+
+// TestExitStatus tests the exit code selection
+func TestExitStatus(t *testing.T) {
+	testCases := []struct {
+		parsedFiles  int
+		trackMissing bool
+		searched     bool
+		matches      int
+		expected     int
+	}{
+		{1, false, false, 0, 0},
+		{2, false, true, 3, 0},
+		{1, false, true, 0, exitNoMatches},
+		{0, false, false, 0, exitError},
+		{0, false, true, 0, exitError},
+		{2, true, false, 0, exitError},
+	}
+
+	for _, tc := range testCases {
+		if got := exitStatus(tc.parsedFiles, tc.trackMissing, tc.searched, tc.matches); got != tc.expected {
+			t.Errorf("exitStatus(%d, %v, %v, %d) = %d, expected %d",
+				tc.parsedFiles, tc.trackMissing, tc.searched, tc.matches, got, tc.expected)
+		}
+	}
+}
+
+// TestCountTrackMatches tests that -ffmpeg only counts whole-track matches
+func TestCountTrackMatches(t *testing.T) {
+	track := &dvd.Track{Index: 1, Length: 2400.0}
+	matches := []dvd.ContentMatch{
+		{Type: "track", Track: track},
+		{Type: "chapter", Track: track, Chapter: &dvd.Chapter{Index: 1}},
+		{Type: "chapter", Track: track, Chapter: &dvd.Chapter{Index: 2}},
+	}
+	if got := countTrackMatches(matches); got != 1 {
+		t.Errorf("countTrackMatches() = %d, expected 1", got)
+	}
+	if got := countTrackMatches(matches[1:]); got != 0 {
+		t.Errorf("countTrackMatches() for chapters only = %d, expected 0", got)
+	}
+}